@@ -11,8 +11,8 @@ three key usage patterns of the lexer library:
 2. Batch processing with statistical analysis
 3. Basic syntax validation and error reporting
 
-Usage: go run main.go <filename>
-Example: go run main.go test.lang
+Usage: go run main.go [--find-keyword <keyword>] <filename>
+Example: go run main.go --find-keyword let test.lang
 
 The tool reads any text file and performs lexical analysis, showing
 tokens, their positions, and any lexical errors encountered.
@@ -21,6 +21,7 @@ tokens, their positions, and any lexical errors encountered.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -28,13 +29,19 @@ import (
 )
 
 func main() {
-	if len(os.Args) != 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <filename>\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "Example: %s test.lang\n", os.Args[0])
+	findKeyword := flag.String("find-keyword", "", "list every occurrence of a keyword")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [--find-keyword <keyword>] <filename>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Example: %s --find-keyword let test.lang\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
 		os.Exit(1)
 	}
 
-	filename := os.Args[1]
+	filename := flag.Arg(0)
 
 	content, err := os.ReadFile(filename)
 	if err != nil {
@@ -54,8 +61,7 @@ func main() {
 		if tok.Type == golexer.EOF {
 			break
 		}
-		fmt.Printf("Type: %-15s Literal: %-15s Line: %2d Column: %2d\n",
-			string(tok.Type), fmt.Sprintf("'%s'", tok.Literal), tok.Line, tok.Column)
+		fmt.Println(tok.String())
 		tokenCount++
 	}
 
@@ -69,8 +75,8 @@ func main() {
 	fmt.Printf("\nProcessed: %d tokens\n", tokenCount)
 	fmt.Println("\n=== Batch processing ===")
 
-	lexer2 := golexer.NewLexer(string(content))
-	tokens, errors := lexer2.TokenizeAll()
+	lexer.Rewind()
+	tokens, errors := lexer.TokenizeAll()
 
 	fmt.Printf("Total tokens: %d\n", len(tokens))
 	fmt.Printf("Total errors: %d\n", len(errors))
@@ -85,6 +91,20 @@ func main() {
 		fmt.Printf("  %-15s: %d\n", tokenType, count)
 	}
 
+	if *findKeyword != "" {
+		fmt.Printf("\n=== Keyword search: %s ===\n", *findKeyword)
+		keywordType := golexer.LookupIdent(*findKeyword)
+		if keywordType == golexer.IDENT {
+			fmt.Printf("%q is not a keyword\n", *findKeyword)
+		} else {
+			matches := golexer.Grep(tokens, golexer.TokenPattern{Type: &keywordType})
+			for _, tok := range matches {
+				fmt.Printf("  %d:%d\n", tok.Line, tok.Column)
+			}
+			fmt.Printf("Found %d occurrence(s)\n", len(matches))
+		}
+	}
+
 	fmt.Println("\n=== Syntax validation ===")
 	if len(errors) == 0 {
 		fmt.Println("✓ No lexical errors found - file is syntactically valid at lexical level")
@@ -97,7 +117,7 @@ func main() {
 
 	fmt.Println("\n=== Summary ===")
 	fmt.Printf("File: %s\n", filename)
-	fmt.Printf("Lines processed: %d\n", getLastLine(tokens))
+	fmt.Printf("Lines processed: %d\n", golexer.LastLine(tokens))
 	fmt.Printf("Tokens generated: %d\n", len(tokens))
 	fmt.Printf("Unique token types: %d\n", len(tokenCounts))
 	fmt.Printf("Lexical errors: %d\n", len(errors))
@@ -109,16 +129,3 @@ func main() {
 		os.Exit(1)
 	}
 }
-
-func getLastLine(tokens []golexer.Token) int {
-	if len(tokens) == 0 {
-		return 1
-	}
-	maxLine := 1
-	for _, token := range tokens {
-		if token.Line > maxLine {
-			maxLine = token.Line
-		}
-	}
-	return maxLine
-}
@@ -0,0 +1,190 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+ANTLR4 Grammar Export
+Writes an ANTLR4 lexer grammar describing the current keyword map,
+operator table and punctuation, so a parser can be generated with
+ANTLR tooling from the same token definitions. Keywords are written
+before IDENT because ANTLR resolves equal-length matches by rule order.
+*/
+
+package golexer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// tokenRule is a token type together with every literal that produces it
+type tokenRule struct {
+	tokType  TokenType
+	literals []string
+}
+
+// tokenRuleSet collects rules in insertion order, merging literals that
+// share a token type into one rule
+type tokenRuleSet struct {
+	rules []tokenRule
+	index map[TokenType]int
+}
+
+func (rs *tokenRuleSet) add(tokType TokenType, literal string) {
+	if i, ok := rs.index[tokType]; ok {
+		for _, existing := range rs.rules[i].literals {
+			if existing == literal {
+				return
+			}
+		}
+		rs.rules[i].literals = append(rs.rules[i].literals, literal)
+		return
+	}
+	rs.index[tokType] = len(rs.rules)
+	rs.rules = append(rs.rules, tokenRule{tokType: tokType, literals: []string{literal}})
+}
+
+// collectTokenRules returns the literally spelled tokens: keywords first,
+// then operators, then punctuation. The counts mark where the keyword and
+// operator sections end.
+func collectTokenRules() (rules []tokenRule, keywordCount, operatorCount int) {
+	rs := &tokenRuleSet{index: make(map[TokenType]int)}
+
+	words := make([]string, 0, len(keywords))
+	for word := range keywords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	for _, word := range words {
+		rs.add(keywords[word], word)
+	}
+	keywordCount = len(rs.rules)
+
+	for _, op := range operators {
+		for _, form := range op.forms() {
+			if form.literal != "" && form.tokType != "" {
+				rs.add(form.tokType, form.literal)
+			}
+		}
+	}
+	operatorCount = len(rs.rules)
+
+	chars := make([]rune, 0, len(singleCharTokens))
+	for ch := range singleCharTokens {
+		chars = append(chars, ch)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	for _, ch := range chars {
+		rs.add(singleCharTokens[ch], string(ch))
+	}
+
+	return rs.rules, keywordCount, operatorCount
+}
+
+// antlrFixedRules are the rules for tokens that are not spelled literally
+var antlrFixedRules = []string{
+	"FLOAT : ([0-9]+ '.' [0-9]+ ([eE] [+-]? [0-9]+)? | [0-9]+ [eE] [+-]? [0-9]+ | '.' [0-9]+ ([eE] [+-]? [0-9]+)?) ('f32' | 'f64' | 'd128')? ;",
+	"INT : ('0' [xX] [0-9a-fA-F]+ | '0' [bB] [01]+ | '0' [oO] [0-7]+ | [0-9]+) [ui] ('8' | '16' | '32' | '64' | 'size') ;",
+	"NUMBER : '0' [xX] [0-9a-fA-F]+ | '0' [bB] [01]+ | '0' [oO] [0-7]+ | [0-9]+ ;",
+	"ANNOTATION : '@' [\\p{L}_] [\\p{L}\\p{Nd}_]* ;",
+	"RAW_STRING : [rR] ('\"' (~[\"\\\\] | '\\\\' .)* '\"' | '\\'' (~['\\\\] | '\\\\' .)* '\\'') ;",
+	"BYTE_STRING : [bB] '\"' (~[\"\\\\\\r\\n] | '\\\\' .)* '\"' ;",
+	"FSTRING_START : [fF] '\"' -> pushMode(FSTRING_DOUBLE) ;",
+	"FSTRING_START_SINGLE : [fF] '\\'' -> type(FSTRING_START), pushMode(FSTRING_SINGLE) ;",
+	"IDENT : [\\p{L}_] [\\p{L}\\p{Nd}_]* ;",
+	"TRIPLE_STRING : '\"\"\"' .*? '\"\"\"' ;",
+	"STRING : '\"' (~[\"\\\\\\r\\n] | '\\\\' .)* '\"' ;",
+	"CHAR : '\\'' (~['\\\\\\r\\n] | '\\\\' .)+ '\\'' ;",
+	"TRIPLE_BACKTICK : '```' .*? '```' ;",
+	"BACKTICK_STRING : '`' ~[`]* '`' ;",
+	"LINE_COMMENT : '//' ~[\\r\\n]* -> skip ;",
+	"BLOCK_COMMENT : '/*' .*? '*/' -> skip ;",
+	"WS : [ \\t\\r\\n]+ -> skip ;",
+}
+
+// antlrFormatStringModes lex the text of f-strings. The "{" opening an
+// expression pushes the default mode, whose RBRACE pops back to the text.
+var antlrFormatStringModes = []string{
+	"mode FSTRING_DOUBLE;",
+	"FSTRING_END : '\"' -> popMode ;",
+	"FSTRING_MIDDLE : (~[{}\"\\\\] | '{{' | '}}' | '\\\\' .)+ ;",
+	"FSTRING_LBRACE : '{' -> type(LBRACE), pushMode(DEFAULT_MODE) ;",
+	"mode FSTRING_SINGLE;",
+	"FSTRING_END_SINGLE : '\\'' -> type(FSTRING_END), popMode ;",
+	"FSTRING_MIDDLE_SINGLE : (~[{}'\\\\] | '{{' | '}}' | '\\\\' .)+ -> type(FSTRING_MIDDLE) ;",
+	"FSTRING_LBRACE_SINGLE : '{' -> type(LBRACE), pushMode(DEFAULT_MODE) ;",
+}
+
+// GenerateANTLR4Grammar writes an ANTLR4 lexer grammar named GoLexer for
+// the current token definitions, including any loaded from a config file.
+// Braces push and pop lexer modes so f-string expressions nest, which
+// means the generated lexer rejects a "}" without a matching "{".
+func GenerateANTLR4Grammar(w io.Writer) error {
+	rules, keywordCount, operatorCount := collectTokenRules()
+
+	var sb strings.Builder
+	sb.WriteString("lexer grammar GoLexer;\n")
+	for i, rule := range rules {
+		switch i {
+		case 0:
+			sb.WriteString("\n// Keywords\n")
+		case keywordCount:
+			sb.WriteString("\n// Operators\n")
+		case operatorCount:
+			sb.WriteString("\n// Punctuation\n")
+		}
+		alternatives := make([]string, len(rule.literals))
+		for j, literal := range rule.literals {
+			alternatives[j] = antlrQuote(literal)
+		}
+		var command string
+		switch rule.tokType {
+		case LBRACE:
+			command = " -> pushMode(DEFAULT_MODE)"
+		case RBRACE:
+			command = " -> popMode"
+		}
+		fmt.Fprintf(&sb, "%s : %s%s ;\n", antlrRuleName(rule.tokType), strings.Join(alternatives, " | "), command)
+	}
+
+	sb.WriteString("\n// Literals, identifiers and skipped input\n")
+	for _, rule := range antlrFixedRules {
+		sb.WriteString(rule)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n// Format string text\n")
+	for _, rule := range antlrFormatStringModes {
+		sb.WriteString(rule)
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// antlrRuleName returns a valid ANTLR lexer rule name for a token type
+func antlrRuleName(tokType TokenType) string {
+	var sb strings.Builder
+	for _, ch := range tokType.String() {
+		if ch < unicode.MaxASCII && (unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_') {
+			sb.WriteRune(unicode.ToUpper(ch))
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	name := sb.String()
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "T_" + name
+	}
+	return name
+}
+
+// antlrQuote returns s as an ANTLR string literal
+func antlrQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,94 @@
+package golexer
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Test that the ANTLR4 grammar covers every keyword, operator and punctuation
+func TestGenerateANTLR4Grammar(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateANTLR4Grammar(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	grammar := buf.String()
+
+	if !strings.HasPrefix(grammar, "lexer grammar GoLexer;\n") {
+		t.Errorf("Expected lexer grammar header, got %q", grammar[:30])
+	}
+
+	for word, tokType := range keywords {
+		rule := antlrRuleName(tokType) + " : "
+		if !strings.Contains(grammar, rule) || !strings.Contains(grammar, "'"+word+"'") {
+			t.Errorf("Expected rule for keyword %q", word)
+		}
+	}
+	for _, op := range operators {
+		for _, form := range op.forms() {
+			if form.tokType != "" && !strings.Contains(grammar, antlrQuote(form.literal)) {
+				t.Errorf("Expected rule for operator %q", form.literal)
+			}
+		}
+	}
+	for ch := range singleCharTokens {
+		if !strings.Contains(grammar, antlrQuote(string(ch))) {
+			t.Errorf("Expected rule for punctuation %q", ch)
+		}
+	}
+
+	// Keywords must come before IDENT so they win ties
+	if strings.Index(grammar, "LET : ") > strings.Index(grammar, "IDENT : ") {
+		t.Errorf("Expected keyword rules before IDENT")
+	}
+
+	// F-string text has its own modes, entered from the default mode
+	for _, rule := range []string{"FSTRING_START : ", "FSTRING_MIDDLE : ", "FSTRING_END : ", "mode FSTRING_DOUBLE;", "RBRACE : '}' -> popMode ;"} {
+		if !strings.Contains(grammar, rule) {
+			t.Errorf("Expected %q in the grammar", rule)
+		}
+	}
+
+	ruleLine := regexp.MustCompile(`^([A-Za-z_0-9]+) : .* ;$`)
+	modeLine := regexp.MustCompile(`^mode [A-Z_]+;$`)
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(grammar, "\n") {
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "lexer grammar") || modeLine.MatchString(line) {
+			continue
+		}
+		m := ruleLine.FindStringSubmatch(line)
+		if m == nil {
+			t.Errorf("Malformed rule: %q", line)
+			continue
+		}
+		if !regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`).MatchString(m[1]) {
+			t.Errorf("Invalid lexer rule name %q", m[1])
+		}
+		if seen[m[1]] {
+			t.Errorf("Duplicate rule %s", m[1])
+		}
+		seen[m[1]] = true
+	}
+}
+
+// Test ANTLR rule names and literal escaping
+func TestANTLRHelpers(t *testing.T) {
+	tests := []struct {
+		tokType  TokenType
+		expected string
+	}{
+		{NOT_EQL, "NOT_EQL"},
+		{TokenType("power"), "POWER"},
+		{TokenType("**"), "T___"},
+	}
+	for _, tt := range tests {
+		if got := antlrRuleName(tt.tokType); got != tt.expected {
+			t.Errorf("TokenType %q: expected %s, got %s", string(tt.tokType), tt.expected, got)
+		}
+	}
+
+	if got := antlrQuote(`'\`); got != `'\'\\'` {
+		t.Errorf("Expected escaped literal, got %s", got)
+	}
+}
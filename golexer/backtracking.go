@@ -0,0 +1,73 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Backtracking Lexer
+Records the tokens read from a lexer so a parser can mark a position,
+try an alternative and rewind to the mark if it fails. Commit drops the
+recorded tokens once the parser no longer needs to go back.
+*/
+
+package golexer
+
+// BacktrackingLexer replays tokens from a lexer between a mark and a rewind
+type BacktrackingLexer struct {
+	lexer   *Lexer
+	history []Token
+	cursor  int
+}
+
+// NewBacktrackingLexer creates a backtracking lexer reading from l
+func NewBacktrackingLexer(l *Lexer) *BacktrackingLexer {
+	return &BacktrackingLexer{lexer: l}
+}
+
+// NextToken returns the next recorded token, reading from the lexer once
+// the history is exhausted
+func (b *BacktrackingLexer) NextToken() Token {
+	if b.cursor < len(b.history) {
+		tok := b.history[b.cursor]
+		b.cursor++
+		return tok
+	}
+
+	tok := b.lexer.NextToken()
+	b.history = append(b.history, tok)
+	b.cursor++
+	return tok
+}
+
+// Mark returns the current position for a later Rewind
+func (b *BacktrackingLexer) Mark() int {
+	return b.cursor
+}
+
+// Rewind moves back to a position returned by Mark. Marks taken before
+// the last Commit are no longer valid.
+func (b *BacktrackingLexer) Rewind(mark int) {
+	if mark < 0 || mark > len(b.history) {
+		return
+	}
+	b.cursor = mark
+}
+
+// Commit discards the tokens before the current position
+func (b *BacktrackingLexer) Commit() {
+	if b.cursor == len(b.history) {
+		b.history = nil
+		b.cursor = 0
+		return
+	}
+
+	remaining := make([]Token, len(b.history)-b.cursor)
+	copy(remaining, b.history[b.cursor:])
+	b.history = remaining
+	b.cursor = 0
+}
+
+// Buffered returns the number of recorded tokens
+func (b *BacktrackingLexer) Buffered() int {
+	return len(b.history)
+}
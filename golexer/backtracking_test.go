@@ -0,0 +1,92 @@
+package golexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test rewinding replays the same tokens and commit prunes history
+func TestBacktrackingLexer(t *testing.T) {
+	b := NewBacktrackingLexer(NewLexer("let x = 10 + y; z"))
+
+	mark := b.Mark()
+	var first []Token
+	for i := 0; i < 5; i++ {
+		first = append(first, b.NextToken())
+	}
+
+	b.Rewind(mark)
+	var second []Token
+	for i := 0; i < 5; i++ {
+		second = append(second, b.NextToken())
+	}
+
+	if !TokenSliceEqual(first, second) {
+		t.Fatalf("Expected %v after rewind, got %v", first, second)
+	}
+	if b.Buffered() != 5 {
+		t.Errorf("Expected 5 buffered tokens, got %d", b.Buffered())
+	}
+
+	// Rewind part way and commit, keeping only the unread tokens
+	b.Rewind(3)
+	b.Commit()
+	if b.Buffered() != 2 || b.Mark() != 0 {
+		t.Errorf("Expected 2 buffered tokens at mark 0 after commit, got %d at %d", b.Buffered(), b.Mark())
+	}
+
+	expected := []TokenType{NUMBER, PLUS, IDENT, SEMICOLON, IDENT, EOF}
+	for _, want := range expected {
+		tok := b.NextToken()
+		if tok.Type != want {
+			t.Errorf("Expected %s, got %s", want, tok.Type)
+		}
+	}
+
+	b.Commit()
+	if b.Buffered() != 0 {
+		t.Errorf("Expected empty history after commit, got %d", b.Buffered())
+	}
+}
+
+// Test invalid marks are ignored
+func TestBacktrackingLexerInvalidRewind(t *testing.T) {
+	b := NewBacktrackingLexer(NewLexer("a b"))
+	b.NextToken()
+
+	b.Rewind(5)
+	if b.Mark() != 1 {
+		t.Errorf("Expected cursor 1 after invalid rewind, got %d", b.Mark())
+	}
+	if tok := b.NextToken(); tok.Literal != "b" {
+		t.Errorf("Expected 'b', got %q", tok.Literal)
+	}
+}
+
+func backtrackingSource() string {
+	// 10 tokens per line, 1000 tokens in total
+	return strings.Repeat("let total = count * 3 + label - 1;\n", 100)
+}
+
+func BenchmarkBacktrackingNoCommit(b *testing.B) {
+	input := backtrackingSource()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bl := NewBacktrackingLexer(NewLexer(input))
+		for bl.NextToken().Type != EOF {
+		}
+	}
+}
+
+func BenchmarkBacktrackingCommit(b *testing.B) {
+	input := backtrackingSource()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bl := NewBacktrackingLexer(NewLexer(input))
+		for bl.NextToken().Type != EOF {
+			bl.Commit()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package golexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// sampleSource is a snippet covering the common token kinds
+const sampleSource = `// compute a running total
+fn total(items, limit) {
+	let sum = 0;
+	for (let i = 0; i < len(items) && i <= limit; i++) {
+		sum += items[i] * 1.5e2 - 0xFF;
+	}
+	if (sum != 0) { print("total: ${sum}\n"); }
+	return sum >= 100 ? 'y' : 'n';
+}
+`
+
+// generateSource repeats sampleSource until it is at least size bytes
+func generateSource(size int) string {
+	var sb strings.Builder
+	for sb.Len() < size {
+		sb.WriteString(sampleSource)
+	}
+	return sb.String()
+}
+
+// benchmarkNextToken lexes input with NextToken on every iteration
+func benchmarkNextToken(b *testing.B, input string) {
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := NewLexer(input)
+		for l.NextToken().Type != EOF {
+		}
+	}
+}
+
+func BenchmarkNextToken_Identifier(b *testing.B) {
+	benchmarkNextToken(b, strings.Repeat("alpha beta_2 gamma δelta _x ", 200))
+}
+
+func BenchmarkNextToken_Number(b *testing.B) {
+	benchmarkNextToken(b, strings.Repeat("42 3.14 1e10 0xFF 0b1010 0o17 1000 ", 200))
+}
+
+func BenchmarkNextToken_String(b *testing.B) {
+	benchmarkNextToken(b, strings.Repeat(`"hello" "tab\tnewline\n" "\u00e9" `, 200))
+}
+
+func BenchmarkNextToken_Operator(b *testing.B) {
+	benchmarkNextToken(b, strings.Repeat("+ - * / == != <= >= && || += -= ++ -- << >> ", 200))
+}
+
+func BenchmarkTokenizeAll_SmallSource(b *testing.B) {
+	b.SetBytes(int64(len(sampleSource)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewLexer(sampleSource).TokenizeAll()
+	}
+}
+
+func BenchmarkTokenizeAll_LargeSource(b *testing.B) {
+	input := generateSource(100 * 1024)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewLexer(input).TokenizeAll()
+	}
+}
+
+func BenchmarkTokenizeAll_ManyErrors(b *testing.B) {
+	input := strings.Repeat("x § 0x 1e+ '' \"\\q\" ", 200)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewLexer(input).TokenizeAll()
+	}
+}
+
+func BenchmarkLookupIdent_Keywords(b *testing.B) {
+	words := []string{"let", "fn", "return", "if", "else", "for", "while", "true"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, w := range words {
+			LookupIdent(w)
+		}
+	}
+}
+
+func BenchmarkLookupIdent_Identifiers(b *testing.B) {
+	words := []string{"x", "counter", "value", "_tmp", "items", "limit", "sum", "lettuce"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, w := range words {
+			LookupIdent(w)
+		}
+	}
+}
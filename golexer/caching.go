@@ -0,0 +1,101 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Caching Lexer
+Memoizes tokenization of recently seen inputs. Useful in a REPL or an
+editor where the same expressions are tokenized again and again. The
+least recently used entry is evicted once the cache is full.
+*/
+
+package golexer
+
+import "container/list"
+
+// CachingLexer tokenizes inputs and caches the results by input
+type CachingLexer struct {
+	maxEntries int
+	opts       []Option
+	entries    map[string]*list.Element
+	order      *list.List
+	newLexer   func(input string, opts ...Option) *Lexer
+}
+
+type cacheEntry struct {
+	input  string
+	tokens []Token
+	errors LexErrorSlice
+}
+
+// NewCachingLexer creates a cache holding up to maxEntries inputs. The
+// options are applied to every lexer it creates.
+func NewCachingLexer(maxEntries int, opts ...Option) *CachingLexer {
+	if maxEntries < 1 {
+		maxEntries = 1
+	}
+	return &CachingLexer{
+		maxEntries: maxEntries,
+		opts:       opts,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		newLexer:   NewLexer,
+	}
+}
+
+// TokenizeAll returns the tokens and errors for input, tokenizing it only
+// if it is not already cached
+func (c *CachingLexer) TokenizeAll(input string) ([]Token, LexErrorSlice) {
+	if el, ok := c.entries[input]; ok {
+		c.order.MoveToFront(el)
+		entry := el.Value.(*cacheEntry)
+		return copyTokens(entry.tokens), copyErrors(entry.errors)
+	}
+
+	tokens, errors := c.newLexer(input, c.opts...).TokenizeAll()
+	c.entries[input] = c.order.PushFront(&cacheEntry{input: input, tokens: tokens, errors: errors})
+
+	if c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).input)
+	}
+
+	return copyTokens(tokens), copyErrors(errors)
+}
+
+// Len returns the number of cached inputs
+func (c *CachingLexer) Len() int {
+	return c.order.Len()
+}
+
+// Clear removes every cached input
+func (c *CachingLexer) Clear() {
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// copyTokens returns a copy of tokens so callers cannot change the cache
+func copyTokens(tokens []Token) []Token {
+	if tokens == nil {
+		return nil
+	}
+	result := make([]Token, len(tokens))
+	copy(result, tokens)
+	return result
+}
+
+// copyErrors returns a copy of errs and of each error in it, so callers
+// cannot change the cache through the slice or the errors it points to
+func copyErrors(errs []*LexError) []*LexError {
+	if errs == nil {
+		return nil
+	}
+	result := make([]*LexError, len(errs))
+	for i, e := range errs {
+		dup := *e
+		result[i] = &dup
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package golexer
+
+import "testing"
+
+// countingCache returns a cache that counts the lexers it creates
+func countingCache(maxEntries int) (*CachingLexer, *int) {
+	calls := 0
+	c := NewCachingLexer(maxEntries)
+	c.newLexer = func(input string, opts ...Option) *Lexer {
+		calls++
+		return NewLexer(input, opts...)
+	}
+	return c, &calls
+}
+
+// Test repeated inputs are served from the cache
+func TestCachingLexerHit(t *testing.T) {
+	c, calls := countingCache(4)
+
+	first, _ := c.TokenizeAll("let x = 1;")
+	second, _ := c.TokenizeAll("let x = 1;")
+
+	if *calls != 1 {
+		t.Errorf("Expected 1 lexer to be created, got %d", *calls)
+	}
+	if !TokenSliceEqual(first, second) {
+		t.Errorf("Expected cached tokens %v, got %v", first, second)
+	}
+
+	// Changing a returned slice must not change the cache
+	second[0].Literal = "changed"
+	third, _ := c.TokenizeAll("let x = 1;")
+	if third[0].Literal != "let" {
+		t.Errorf("Expected cached literal 'let', got %q", third[0].Literal)
+	}
+}
+
+// Test different inputs are cached independently along with their errors
+func TestCachingLexerIndependentEntries(t *testing.T) {
+	c, calls := countingCache(4)
+
+	a, errsA := c.TokenizeAll("a + b")
+	b, errsB := c.TokenizeAll("x § y")
+
+	if *calls != 2 || c.Len() != 2 {
+		t.Errorf("Expected 2 lexers and 2 entries, got %d and %d", *calls, c.Len())
+	}
+	if TokenSliceEqual(a, b) {
+		t.Errorf("Expected different tokens for different inputs")
+	}
+	if len(errsA) != 0 || len(errsB) != 1 {
+		t.Errorf("Expected 0 and 1 errors, got %d and %d", len(errsA), len(errsB))
+	}
+
+	if _, errs := c.TokenizeAll("x § y"); len(errs) != 1 || *calls != 2 {
+		t.Errorf("Expected cached error without relexing, got %d errors and %d lexers", len(errs), *calls)
+	}
+
+	// Changing a returned error must not change the cache
+	errsB[0].Message = "changed"
+	errsB[0] = nil
+	if _, errs := c.TokenizeAll("x § y"); errs[0] == nil || errs[0].Message == "changed" {
+		t.Errorf("Expected the cached error to be unchanged, got %v", errs[0])
+	}
+}
+
+// Test the least recently used entry is evicted when the cache is full
+func TestCachingLexerEviction(t *testing.T) {
+	c, calls := countingCache(2)
+
+	c.TokenizeAll("a")
+	c.TokenizeAll("b")
+	c.TokenizeAll("a") // a is now the most recently used
+	c.TokenizeAll("c") // evicts b
+
+	if c.Len() != 2 || *calls != 3 {
+		t.Fatalf("Expected 2 entries and 3 lexers, got %d and %d", c.Len(), *calls)
+	}
+
+	c.TokenizeAll("a")
+	if *calls != 3 {
+		t.Errorf("Expected 'a' to still be cached")
+	}
+	c.TokenizeAll("b")
+	if *calls != 4 {
+		t.Errorf("Expected 'b' to have been evicted")
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("Expected empty cache after Clear, got %d", c.Len())
+	}
+}
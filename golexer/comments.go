@@ -0,0 +1,56 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Comment Attachment
+Associates comments with the tokens they document, for tools such as
+documentation generators. Requires a token stream produced with
+WithEmitComments(true).
+*/
+
+package golexer
+
+import "strings"
+
+// AttachComments returns the non-comment tokens of a stream with their
+// comments attached. A line or doc comment that ends on the line just
+// before a token, with only comments in between, becomes that token's
+// LeadingComment. A line comment following a token on the same line
+// becomes that token's TrailingComment.
+func AttachComments(tokens []Token) []Token {
+	result := make([]Token, 0, len(tokens))
+	var leading *Token
+
+	for _, tok := range tokens {
+		if tok.IsComment() {
+			comment := tok
+			if tok.Type == LINE_COMMENT && len(result) > 0 {
+				last := &result[len(result)-1]
+				if last.Line == tok.Line && last.TrailingComment == nil {
+					last.TrailingComment = &comment
+					leading = nil
+					continue
+				}
+			}
+			if tok.Type != BLOCK_COMMENT {
+				leading = &comment
+			}
+			continue
+		}
+
+		if leading != nil && tok.Type != EOF && commentEndLine(*leading) == tok.Line-1 {
+			tok.LeadingComment = leading
+		}
+		leading = nil
+		result = append(result, tok)
+	}
+
+	return result
+}
+
+// commentEndLine returns the line a comment token ends on
+func commentEndLine(tok Token) int {
+	return tok.Line + strings.Count(tok.Literal, "\n")
+}
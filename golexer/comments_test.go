@@ -0,0 +1,126 @@
+package golexer
+
+import (
+	"testing"
+)
+
+// Test comment tokens emitted with WithEmitComments
+func TestEmitComments(t *testing.T) {
+	input := "/// doc\n// line\n/** block doc */ /* block */ /**/ //// rule\nx"
+	tokens, errors := NewLexer(input, WithEmitComments(true)).TokenizeAll()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	expected := []Token{
+		NewToken(DOC_COMMENT, "/// doc", 1, 1),
+		NewToken(LINE_COMMENT, "// line", 2, 1),
+		NewToken(DOC_COMMENT, "/** block doc */", 3, 1),
+		NewToken(BLOCK_COMMENT, "/* block */", 3, 18),
+		NewToken(BLOCK_COMMENT, "/**/", 3, 30),
+		NewToken(LINE_COMMENT, "//// rule", 3, 35),
+		NewToken(IDENT, "x", 4, 1),
+	}
+	if !TokenSliceEqual(tokens, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+	for i := range expected {
+		if tokens[i].Column != expected[i].Column {
+			t.Errorf("Token %d: expected column %d, got %d", i, expected[i].Column, tokens[i].Column)
+		}
+	}
+
+	tokens, _ = NewLexer(input).TokenizeAll()
+	if len(tokens) != 1 || tokens[0].Type != IDENT {
+		t.Errorf("Expected comments to be skipped by default, got %v", tokens)
+	}
+}
+
+// Test attaching doc and inline comments to a declaration
+func TestAttachComments(t *testing.T) {
+	input := "/// Add returns the sum\n" +
+		"fn add(a, b) { // inline\n" +
+		"    return a + b\n" +
+		"}\n" +
+		"\n" +
+		"// detached\n" +
+		"\n" +
+		"let x = 1"
+
+	tokens, _ := NewLexer(input, WithEmitComments(true)).TokenizeAll()
+	attached := AttachComments(tokens)
+
+	for _, tok := range attached {
+		if tok.IsComment() {
+			t.Fatalf("Expected comments to be removed, got %v", tok)
+		}
+	}
+
+	fn := attached[0]
+	if fn.Type != FN || fn.LeadingComment == nil || fn.LeadingComment.Literal != "/// Add returns the sum" {
+		t.Errorf("Expected fn to have the doc comment, got %+v", fn.LeadingComment)
+	}
+
+	var lbrace, let Token
+	for _, tok := range attached {
+		switch tok.Type {
+		case LBRACE:
+			lbrace = tok
+		case LET:
+			let = tok
+		}
+		if tok.Type != LBRACE && tok.TrailingComment != nil {
+			t.Errorf("Unexpected trailing comment on %s", tok.Type)
+		}
+		if tok.Type != FN && tok.LeadingComment != nil {
+			t.Errorf("Unexpected leading comment on %s: %q", tok.Type, tok.LeadingComment.Literal)
+		}
+	}
+	if lbrace.TrailingComment == nil || lbrace.TrailingComment.Literal != "// inline" {
+		t.Errorf("Expected '{' to have the inline comment, got %+v", lbrace.TrailingComment)
+	}
+	if let.LeadingComment != nil {
+		t.Errorf("Expected comment separated by a blank line not to attach")
+	}
+}
+
+// Test a configured line comment character and the "//" toggle
+func TestLineCommentPrefix(t *testing.T) {
+	setSingleCharToken(t, '#', "HASH")
+
+	input := "x # inline comment\ny = 2 // slash"
+	tokens, errors := NewLexer(input, WithLineCommentPrefix('#')).TokenizeAll()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+	expected := []Token{
+		NewToken(IDENT, "x", 1, 1),
+		NewToken(IDENT, "y", 2, 1),
+		NewToken(ASSIGN, "=", 2, 3),
+		NewToken(NUMBER, "2", 2, 5),
+	}
+	if !TokenSliceEqual(tokens, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+
+	tokens, _ = NewLexer(input, WithLineCommentPrefix('#'), WithEmitComments(true)).TokenizeAll()
+	if len(tokens) != 6 || tokens[1].Type != LINE_COMMENT || tokens[1].Literal != "# inline comment" || tokens[5].Literal != "// slash" {
+		t.Errorf("Expected both comments to be emitted, got %v", tokens)
+	}
+
+	tokens, _ = NewLexer(input).TokenizeAll()
+	if len(tokens) < 2 || tokens[1].Type != "HASH" {
+		t.Errorf("Expected HASH without a comment prefix, got %v", tokens)
+	}
+
+	tokens, _ = NewLexer("a // b", WithSlashComments(false)).TokenizeAll()
+	expected = []Token{
+		NewToken(IDENT, "a", 1, 1),
+		NewToken(DIVIDE, "/", 1, 3),
+		NewToken(DIVIDE, "/", 1, 4),
+		NewToken(IDENT, "b", 1, 6),
+	}
+	if !TokenSliceEqual(tokens, expected) {
+		t.Errorf("Expected %v with slash comments off, got %v", expected, tokens)
+	}
+}
@@ -1,36 +1,218 @@
-// golexer/config.go
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Configuration
+Loads JSON config files that extend the built-in keywords, operators and
+punctuation, and applies them to the package tables or to one lexer.
+*/
+
 package golexer
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"sort"
 )
 
+// Config describes the tokens a config file adds to the built-in set.
+// Each map goes from the literal to the name of its token type.
 type Config struct {
-	AdditionalKeywords    map[string]string `json:"additionalKeywords"`
-	AdditionalOperators   map[string]string `json:"additionalOperators"`
-	AdditionalPunctuation map[string]string `json:"additionalPunctuation"`
+	AdditionalKeywords    map[string]string     `json:"additionalKeywords"`
+	AdditionalOperators   map[string]string     `json:"additionalOperators"`
+	AdditionalPunctuation map[string]string     `json:"additionalPunctuation"`
+	StringDelimiters      []StringDelimiterPair `json:"stringDelimiters,omitempty"`
+}
+
+// StringDelimiterPair defines a string literal opened by Open and closed
+// by Close, such as «text». TokenType defaults to STRING when empty.
+type StringDelimiterPair struct {
+	Open         string `json:"open"`
+	Close        string `json:"close"`
+	TokenType    string `json:"tokenType"`
+	AllowEscapes bool   `json:"allowEscapes"`
+}
+
+// DefaultConfig returns a config that adds nothing to the built-in tokens
+func DefaultConfig() *Config {
+	return &Config{
+		AdditionalKeywords:    map[string]string{},
+		AdditionalOperators:   map[string]string{},
+		AdditionalPunctuation: map[string]string{},
+	}
+}
+
+// Validate reports token type names in the config that look like
+// misspelled built-in types, such as "IDNET" for IDENT, and operators
+// that would be ambiguous with the built-in operator table. Other unknown
+// names are new types defined by the config and are accepted.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(section, literal, name string) {
+		err := TokenType(name).Validate()
+		if err == nil {
+			return
+		}
+		if _, misspelled := suggestTokenType(TokenType(name)); misspelled {
+			errs = append(errs, fmt.Errorf("%s %q: %w", section, literal, err))
+		}
+	}
+
+	sections := []struct {
+		name  string
+		types map[string]string
+	}{
+		{"additionalKeywords", c.AdditionalKeywords},
+		{"additionalOperators", c.AdditionalOperators},
+		{"additionalPunctuation", c.AdditionalPunctuation},
+	}
+	for _, section := range sections {
+		literals := make([]string, 0, len(section.types))
+		for literal := range section.types {
+			literals = append(literals, literal)
+		}
+		sort.Strings(literals)
+		for _, literal := range literals {
+			check(section.name, literal, section.types[literal])
+		}
+	}
+	for _, pair := range c.StringDelimiters {
+		if pair.TokenType != "" {
+			check("stringDelimiters", pair.Open, pair.TokenType)
+		}
+	}
+
+	table := c.addOperators(append([]Operator(nil), operators...), map[TokenType]struct{}{})
+	for _, warning := range validateOperatorTable(table) {
+		errs = append(errs, errors.New(warning))
+	}
+
+	return errors.Join(errs...)
 }
 
+// MergeWithDefaults adds the config's keywords, operators and
+// single-character punctuation to the package tables, so they apply to
+// every lexer created afterwards. Lexers that already exist keep the
+// operators and punctuation they were created with, but see the new
+// keywords. Longer punctuation and string delimiters
+// are not merged; NewLexerWithConfig applies them to its own lexer.
+// Validate reports operators the merge would make ambiguous.
 func (c *Config) MergeWithDefaults() {
+	c.mergeKeywords()
+
+	operators = c.addOperators(operators, operatorTypes)
+	SortOperators()
+
+	for char, tokenType := range c.AdditionalPunctuation {
+		if len(char) == 1 {
+			singleCharTokens[rune(char[0])] = TokenType(tokenType)
+		}
+	}
+}
+
+// mergeKeywords adds the config's keywords to the package keyword table
+func (c *Config) mergeKeywords() {
 	for keyword, tokenType := range c.AdditionalKeywords {
 		keywords[keyword] = TokenType(tokenType)
+		keywordTypes[TokenType(tokenType)] = struct{}{}
 	}
+	rebuildKeywordIndex()
+}
 
+// addOperators appends the config's operators to ops, unsorted, and
+// records their token types in types
+func (c *Config) addOperators(ops []Operator, types map[TokenType]struct{}) []Operator {
 	for op, tokenType := range c.AdditionalOperators {
-		operators = append(operators, Operator{
+		ops = append(ops, Operator{
 			Single:     op,
 			SingleType: TokenType(tokenType),
 		})
+		types[TokenType(tokenType)] = struct{}{}
 	}
+	return ops
+}
 
-	for char, tokenType := range c.AdditionalPunctuation {
-		if len(char) == 1 {
-			singleCharTokens[rune(char[0])] = TokenType(tokenType)
+// applyConfig adds a config to this lexer only: its keywords, operators,
+// punctuation and string delimiters are matched by the lexer that loaded
+// them and leave the package tables unchanged.
+// It returns a warning for each operator that is now ambiguous.
+func (l *Lexer) applyConfig(c *Config) []string {
+	for keyword, tokenType := range c.AdditionalKeywords {
+		if l.keywords == nil {
+			l.keywords = make(map[string]TokenType)
+			l.keywordTypes = make(map[TokenType]struct{})
 		}
+		l.keywords[keyword] = TokenType(tokenType)
+		l.keywordTypes[TokenType(tokenType)] = struct{}{}
 	}
+
+	if l.operatorTypes == nil {
+		l.operatorTypes = make(map[TokenType]struct{})
+	}
+	l.operators = c.addOperators(l.operators, l.operatorTypes)
+	sortOperators(l.operators)
+	l.operatorOrder = matchOrder(l.operators)
+
+	for punct, tokenType := range c.AdditionalPunctuation {
+		switch {
+		case len(punct) == 0:
+			continue
+		case len(punct) == 1:
+			l.singleChars[rune(punct[0])] = TokenType(tokenType)
+			continue
+		}
+		if l.multiCharTokens == nil {
+			l.multiCharTokens = make(map[string]TokenType)
+		}
+		l.multiCharTokens[punct] = TokenType(tokenType)
+	}
+
+	for _, pair := range c.StringDelimiters {
+		if pair.Open == "" || pair.Close == "" {
+			continue
+		}
+		if pair.TokenType == "" {
+			pair.TokenType = string(STRING)
+		}
+		l.stringDelimiters = append(l.stringDelimiters, pair)
+	}
+	return validateOperatorTable(l.operators)
+}
+
+// lookupIdent returns the token type for an identifier, checking the
+// keywords of this lexer's config before the package table
+func (l *Lexer) lookupIdent(ident string) TokenType {
+	if tok, ok := l.keywords[ident]; ok {
+		return tok
+	}
+	return LookupIdent(ident)
+}
+
+// IsKeyword reports whether tok is a keyword, including the keywords of
+// the config this lexer was created with
+func (l *Lexer) IsKeyword(tok Token) bool {
+	if _, ok := l.keywordTypes[tok.Type]; ok {
+		return true
+	}
+	return tok.IsKeyword()
 }
 
+// IsOperator reports whether tok is an operator, including the operators
+// of the config this lexer was created with
+func (l *Lexer) IsOperator(tok Token) bool {
+	if _, ok := l.operatorTypes[tok.Type]; ok {
+		return true
+	}
+	return tok.IsOperator()
+}
+
+// LoadConfig reads a JSON config file. It does not change any lexer
+// until the config is merged or passed to NewLexerWithConfig.
 func LoadConfig(filename string) (*Config, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
@@ -41,3 +223,77 @@ func LoadConfig(filename string) (*Config, error) {
 	err = json.Unmarshal(data, &config)
 	return &config, err
 }
+
+// ToJSON encodes the config in the config file format
+func (c *Config) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// WriteJSON writes the config to w in the config file format
+func (c *Config) WriteJSON(w io.Writer) error {
+	data, err := c.ToJSON()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// LoadConfigFromEnv builds a config from environment variables. Each
+// variable holds a JSON object in the same format as the config file:
+//
+//	PREFIX_KEYWORDS='{"unless": "UNLESS"}'
+//	PREFIX_OPERATORS='{"**": "POWER"}'
+//	PREFIX_PUNCTUATION='{"@": "AT"}'
+//
+// Unset variables are left empty. It returns an error if none are set or
+// a value is not a valid JSON object.
+func LoadConfigFromEnv(prefix string) (*Config, error) {
+	config, found, err := loadConfigFromEnv(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no %s_KEYWORDS, %s_OPERATORS or %s_PUNCTUATION environment variables set", prefix, prefix, prefix)
+	}
+	return config, nil
+}
+
+// LoadConfigFromEnvOrFile loads the config from environment variables if
+// any are set and from fallbackFile otherwise
+func LoadConfigFromEnvOrFile(prefix, fallbackFile string) (*Config, error) {
+	config, found, err := loadConfigFromEnv(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return LoadConfig(fallbackFile)
+	}
+	return config, nil
+}
+
+func loadConfigFromEnv(prefix string) (*Config, bool, error) {
+	var config Config
+	found := false
+
+	vars := []struct {
+		suffix string
+		target *map[string]string
+	}{
+		{"_KEYWORDS", &config.AdditionalKeywords},
+		{"_OPERATORS", &config.AdditionalOperators},
+		{"_PUNCTUATION", &config.AdditionalPunctuation},
+	}
+	for _, v := range vars {
+		value, ok := os.LookupEnv(prefix + v.suffix)
+		if !ok {
+			continue
+		}
+		found = true
+		if err := json.Unmarshal([]byte(value), v.target); err != nil {
+			return nil, false, fmt.Errorf("invalid %s%s: %w", prefix, v.suffix, err)
+		}
+	}
+
+	return &config, found, nil
+}
@@ -0,0 +1,192 @@
+package golexer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test loading a config from environment variables
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Setenv("MYLANG_KEYWORDS", `{"unless": "UNLESS", "until": "UNTIL"}`)
+	t.Setenv("MYLANG_OPERATORS", `{"**": "POWER"}`)
+
+	config, err := LoadConfigFromEnv("MYLANG")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.AdditionalKeywords["unless"] != "UNLESS" || config.AdditionalKeywords["until"] != "UNTIL" {
+		t.Errorf("Unexpected keywords: %v", config.AdditionalKeywords)
+	}
+	if config.AdditionalOperators["**"] != "POWER" {
+		t.Errorf("Unexpected operators: %v", config.AdditionalOperators)
+	}
+	if len(config.AdditionalPunctuation) != 0 {
+		t.Errorf("Expected no punctuation, got %v", config.AdditionalPunctuation)
+	}
+
+	if _, err := LoadConfigFromEnv("UNSET_PREFIX"); err == nil {
+		t.Errorf("Expected an error when no variables are set")
+	}
+
+	t.Setenv("BROKEN_PUNCTUATION", `{"@": `)
+	if _, err := LoadConfigFromEnv("BROKEN"); err == nil {
+		t.Errorf("Expected an error for invalid JSON")
+	}
+}
+
+// Test falling back to a config file
+func TestLoadConfigFromEnvOrFile(t *testing.T) {
+	config, err := LoadConfigFromEnvOrFile("UNSET_PREFIX", "../examples/config.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.AdditionalKeywords["async"] != "ASYNC" {
+		t.Errorf("Expected config from file, got %v", config.AdditionalKeywords)
+	}
+
+	t.Setenv("MYLANG_PUNCTUATION", `{"@": "AT"}`)
+	config, err = LoadConfigFromEnvOrFile("MYLANG", "../examples/config.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.AdditionalPunctuation["@"] != "AT" || len(config.AdditionalKeywords) != 0 {
+		t.Errorf("Expected config from the environment, got %+v", config)
+	}
+
+	if _, err := LoadConfigFromEnvOrFile("UNSET_PREFIX", "missing.json"); err == nil {
+		t.Errorf("Expected an error for a missing fallback file")
+	}
+}
+
+// Test writing a config back to JSON
+func TestConfigToJSON(t *testing.T) {
+	config, err := LoadConfig("../examples/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.WriteJSON(f); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f.Close()
+
+	reloaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(config, reloaded) {
+		t.Errorf("Expected %+v, got %+v", config, reloaded)
+	}
+
+	data, err := DefaultConfig().ToJSON()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !json.Valid(data) {
+		t.Fatalf("Expected valid JSON, got %s", data)
+	}
+	var decoded Config
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, DefaultConfig()) {
+		t.Errorf("Expected default config to round-trip, got %+v", decoded)
+	}
+}
+
+// Test that operators and punctuation from a config stay with one lexer
+func TestConfigOperatorsArePerLexer(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{"additionalOperators": {"%%": "PERCENT_PERCENT"}, "additionalPunctuation": {"\\": "BACKSLASH"}}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	input := `a %% b \ c`
+
+	configured := NewLexerWithConfig(input, configFile)
+	tokens, errors := configured.TokenizeAll()
+	if len(errors) != 0 || len(tokens) != 5 || tokens[1].Type != "PERCENT_PERCENT" || tokens[3].Type != "BACKSLASH" {
+		t.Errorf("Expected the configured lexer to use the config, got %v with errors %v", tokens, errors)
+	}
+	if len(tokens) > 1 && (!configured.IsOperator(tokens[1]) || tokens[1].IsOperator()) {
+		t.Errorf("Expected PERCENT_PERCENT to be an operator only for the configured lexer")
+	}
+
+	tokens, errors = NewLexer(input).TokenizeAll()
+	if len(errors) != 1 || len(tokens) != 6 || tokens[1].Type != MODULUS || tokens[2].Type != MODULUS || tokens[4].Type != ILLEGAL {
+		t.Errorf("Expected a plain lexer not to see the config, got %v with errors %v", tokens, errors)
+	}
+
+	// Keywords from a config are per lexer too
+	if err := os.WriteFile(configFile, []byte(`{"additionalKeywords": {"unless": "UNLESS"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	configured = NewLexerWithConfig("unless", configFile)
+	if tok := configured.NextToken(); tok.Type != "UNLESS" || !configured.IsKeyword(tok) || tok.IsKeyword() {
+		t.Errorf("Expected UNLESS as a keyword of the configured lexer only, got %s", tok.Type)
+	}
+	if tok := NewLexer("unless").NextToken(); tok.Type != IDENT {
+		t.Errorf("Expected a plain lexer to read unless as IDENT, got %s", tok.Type)
+	}
+
+	// Lexers keep the tables they were created with
+	l := NewLexer(`\`)
+	setSingleCharToken(t, '\\', "BACKSLASH")
+	if tok := l.NextToken(); tok.Type != ILLEGAL {
+		t.Errorf("Expected an existing lexer to ignore later table changes, got %s", tok.Type)
+	}
+	if tok := NewLexer(`\`).NextToken(); tok.Type != "BACKSLASH" {
+		t.Errorf("Expected a new lexer to use the package table, got %s", tok.Type)
+	}
+}
+
+// Test that Validate catches misspelled token types and ambiguous operators
+func TestConfigValidate(t *testing.T) {
+	config, err := LoadConfig("../examples/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected the example config to be valid, got %v", err)
+	}
+
+	config = &Config{
+		AdditionalKeywords:    map[string]string{"unless": "UNLESS", "var": "IDNET"},
+		AdditionalOperators:   map[string]string{"**": "POWER", ":=": "ASSIGN"},
+		AdditionalPunctuation: map[string]string{";;": "semicolon"},
+		StringDelimiters:      []StringDelimiterPair{{Open: "<<", Close: ">>", TokenType: "STRNG"}},
+	}
+	err = config.Validate()
+	if err == nil {
+		t.Fatalf("Expected misspelled types to be reported")
+	}
+	for _, want := range []string{
+		`additionalKeywords "var": unknown token type "IDNET" (did you mean "IDENT"?)`,
+		`additionalOperators ":=": unknown token type "ASSIGN" (did you mean "="?)`,
+		`additionalPunctuation ";;": unknown token type "semicolon" (did you mean ";"?)`,
+		`stringDelimiters "<<": unknown token type "STRNG" (did you mean "STRING"?)`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %q", want, err.Error())
+		}
+	}
+	if strings.Contains(err.Error(), "UNLESS") || strings.Contains(err.Error(), "POWER") {
+		t.Errorf("Expected new types to be accepted, got %q", err.Error())
+	}
+
+	// Operators are checked against the built-in table
+	config = &Config{AdditionalOperators: map[string]string{"+": "STRING_JOIN"}}
+	err = config.Validate()
+	if want := "operator '+' is ambiguous at priority 0: PLUS and STRING_JOIN"; err == nil || err.Error() != want {
+		t.Errorf("Expected %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Token Stream Diffing
+Compares two tokenizations of the same source, for example before and
+after changing the lexer. Tokens are matched on type and literal using
+a longest common subsequence, so positions do not affect the result.
+*/
+
+package golexer
+
+// TokenDiff describes one difference between two token streams. Old is nil
+// for a token only in the new stream and New is nil for a token only in the
+// old stream; a changed token has both. Index is the position of Old in
+// the old stream, or of New in the new stream for insertions.
+type TokenDiff struct {
+	Index int
+	Old   *Token
+	New   *Token
+}
+
+// DiffTokenStreams returns the differences between token streams a and b.
+// A deleted token directly followed by an inserted one is reported as a
+// single change.
+func DiffTokenStreams(a, b []Token) []TokenDiff {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i].Equal(b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diffs []TokenDiff
+	var deleted, inserted []int
+
+	// flush pairs up pending deletions and insertions as changes
+	flush := func() {
+		for k := 0; k < len(deleted) || k < len(inserted); k++ {
+			switch {
+			case k < len(deleted) && k < len(inserted):
+				diffs = append(diffs, TokenDiff{Index: deleted[k], Old: &a[deleted[k]], New: &b[inserted[k]]})
+			case k < len(deleted):
+				diffs = append(diffs, TokenDiff{Index: deleted[k], Old: &a[deleted[k]]})
+			default:
+				diffs = append(diffs, TokenDiff{Index: inserted[k], New: &b[inserted[k]]})
+			}
+		}
+		deleted, inserted = deleted[:0], inserted[:0]
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i].Equal(b[j]):
+			flush()
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			deleted = append(deleted, i)
+			i++
+		default:
+			inserted = append(inserted, j)
+			j++
+		}
+	}
+	flush()
+
+	return diffs
+}
@@ -0,0 +1,68 @@
+package golexer
+
+import (
+	"testing"
+)
+
+// Test diffing streams that differ in a single token
+func TestDiffTokenStreamsChange(t *testing.T) {
+	a, _ := NewLexer("let x = a + b;").TokenizeAll()
+	b, _ := NewLexer("let x = a - b;").TokenizeAll()
+
+	diffs := DiffTokenStreams(a, b)
+	if len(diffs) != 1 {
+		t.Fatalf("Expected 1 diff, got %d: %v", len(diffs), diffs)
+	}
+	d := diffs[0]
+	if d.Index != 4 || d.Old == nil || d.New == nil || d.Old.Type != PLUS || d.New.Type != MINUS {
+		t.Errorf("Expected + changed to - at index 4, got %+v", d)
+	}
+
+	if diffs := DiffTokenStreams(a, a); len(diffs) != 0 {
+		t.Errorf("Expected identical streams to have no diffs, got %v", diffs)
+	}
+}
+
+// Test insertions and deletions
+func TestDiffTokenStreamsInsertDelete(t *testing.T) {
+	a, _ := NewLexer("f(x)").TokenizeAll()
+	b, _ := NewLexer("f(x, y)").TokenizeAll()
+
+	diffs := DiffTokenStreams(a, b)
+	if len(diffs) != 2 {
+		t.Fatalf("Expected 2 insertions, got %v", diffs)
+	}
+	for k, d := range diffs {
+		if d.Old != nil || d.New == nil || d.Index != 3+k {
+			t.Errorf("Diff %d: expected insertion at %d, got %+v", k, 3+k, d)
+		}
+	}
+
+	diffs = DiffTokenStreams(b, a)
+	if len(diffs) != 2 || diffs[0].New != nil || diffs[0].Old.Type != COMMA || diffs[1].Old.Literal != "y" {
+		t.Errorf("Expected 2 deletions, got %v", diffs)
+	}
+}
+
+// Test completely disjoint streams
+func TestDiffTokenStreamsDisjoint(t *testing.T) {
+	a, _ := NewLexer("a b").TokenizeAll()
+	b, _ := NewLexer("1 2 3").TokenizeAll()
+
+	diffs := DiffTokenStreams(a, b)
+	if len(diffs) != 3 {
+		t.Fatalf("Expected 3 diffs, got %v", diffs)
+	}
+	for k := 0; k < 2; k++ {
+		if diffs[k].Old == nil || diffs[k].New == nil {
+			t.Errorf("Diff %d: expected a change, got %+v", k, diffs[k])
+		}
+	}
+	if diffs[2].Old != nil || diffs[2].New.Literal != "3" {
+		t.Errorf("Expected final insertion of 3, got %+v", diffs[2])
+	}
+
+	if diffs := DiffTokenStreams(nil, b); len(diffs) != 3 {
+		t.Errorf("Expected 3 insertions against an empty stream, got %d", len(diffs))
+	}
+}
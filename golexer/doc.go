@@ -0,0 +1,26 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Package Documentation
+Holds the package comment shown by go doc.
+*/
+
+// Package golexer is a configurable lexical analyzer for C-like and Go-like
+// languages. It turns source text into a stream of Tokens with line,
+// column and byte offset positions, and collects LexErrors instead of
+// stopping at the first problem.
+//
+// A Lexer is created with NewLexer and functional Options, or with
+// NewLexerWithConfig to add keywords, operators, punctuation and string
+// delimiters from a JSON Config file. Tokens are read one at a time with
+// NextToken, or all at once with TokenizeAll:
+//
+//	l := golexer.NewLexer(`let x = 42;`)
+//	tokens, errs := l.TokenizeAll()
+//
+// Keywords, operators and single-character punctuation are shared package
+// tables, so MergeWithDefaults affects every lexer in the process.
+package golexer
@@ -18,16 +18,243 @@ Features:
 
 package golexer
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrorSeverity distinguishes errors from warnings
+type ErrorSeverity int
+
+const (
+	SeverityError ErrorSeverity = iota
+	SeverityWarning
+)
+
+// Error codes identifying the kind of a LexError
+const (
+	CodeUnexpectedChar = iota + 1
+	CodeInvalidNumber
+	CodeInvalidEscape
+	CodeInvalidIdentifier
+	CodeUnterminatedString
+	CodeUnterminatedChar
+	CodeUnterminatedComment
+	CodeInvalidIndentation
+	CodeInputTooLarge
+	CodeNonASCIIByte
+)
 
 // LexError represents a lexical analysis error with position information
 type LexError struct {
-	Message string
-	Line    int
-	Column  int
+	Message  string
+	Line     int
+	Column   int
+	Code     int
+	Severity ErrorSeverity
+
+	// EndLine and EndColumn give the last character of the text the error
+	// covers, such as the rest of a malformed token. They are 0 when the
+	// error has no span.
+	EndLine   int
+	EndColumn int
+
+	// SourceFile is the name given with WithSourceName, if any
+	SourceFile string
 }
 
-// Error implements the error interface
+// Error implements the error interface. Errors with a source file use the
+// compiler style file:line:column prefix.
 func (e *LexError) Error() string {
+	if e.SourceFile != "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.SourceFile, e.Line, e.Column, e.Message)
+	}
 	return fmt.Sprintf("lexical error at line %d, column %d: %s", e.Line, e.Column, e.Message)
 }
+
+// SetSourceFile sets the file name reported by the error and returns it
+func (e *LexError) SetSourceFile(name string) *LexError {
+	e.SourceFile = name
+	return e
+}
+
+// GetErrorsWhere returns the errors for which pred returns true
+func (l *Lexer) GetErrorsWhere(pred func(*LexError) bool) []*LexError {
+	var matched []*LexError
+	for _, e := range l.errors {
+		if pred(e) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// GetErrorsOnLine returns the errors reported on the given line
+func (l *Lexer) GetErrorsOnLine(line int) []*LexError {
+	return l.GetErrorsWhere(func(e *LexError) bool { return e.Line == line })
+}
+
+// GetErrorsWithCode returns the errors with the given code
+func (l *Lexer) GetErrorsWithCode(code int) []*LexError {
+	return l.GetErrorsWhere(func(e *LexError) bool { return e.Code == code })
+}
+
+// GetWarnings returns the errors with warning severity
+func (l *Lexer) GetWarnings() []*LexError {
+	return l.GetErrorsWhere(func(e *LexError) bool { return e.Severity == SeverityWarning })
+}
+
+// ClearErrors discards all collected errors
+func (l *Lexer) ClearErrors() {
+	l.errors = make([]*LexError, 0)
+}
+
+// Sentinel errors for use with errors.Is. A LexError matches a sentinel
+// when their codes are equal.
+var (
+	ErrUnterminatedString  = &LexError{Message: "unterminated string literal", Code: CodeUnterminatedString}
+	ErrUnterminatedChar    = &LexError{Message: "unterminated character literal", Code: CodeUnterminatedChar}
+	ErrInvalidEscape       = &LexError{Message: "invalid escape sequence", Code: CodeInvalidEscape}
+	ErrInvalidNumber       = &LexError{Message: "invalid number", Code: CodeInvalidNumber}
+	ErrUnexpectedChar      = &LexError{Message: "unexpected character", Code: CodeUnexpectedChar}
+	ErrUnterminatedComment = &LexError{Message: "unterminated block comment", Code: CodeUnterminatedComment}
+)
+
+// Unwrap returns the underlying error, which is always nil for now
+func (e *LexError) Unwrap() error {
+	return nil
+}
+
+// Is reports whether target is a LexError with the same non-zero code
+func (e *LexError) Is(target error) bool {
+	t, ok := target.(*LexError)
+	return ok && t.Code != 0 && t.Code == e.Code
+}
+
+// As sets target to e if target is a **LexError
+func (e *LexError) As(target interface{}) bool {
+	if t, ok := target.(**LexError); ok {
+		*t = e
+		return true
+	}
+	return false
+}
+
+// LexErrorSlice is a list of lexical errors that can be returned as a
+// single error
+type LexErrorSlice []*LexError
+
+// Error joins the messages of all errors, one per line
+func (s LexErrorSlice) Error() string {
+	messages := make([]string, len(s))
+	for i, e := range s {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// HasCode reports whether any error has the given code
+func (s LexErrorSlice) HasCode(code int) bool {
+	for _, e := range s {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSeverity reports whether any error has the given severity
+func (s LexErrorSlice) HasSeverity(severity ErrorSeverity) bool {
+	for _, e := range s {
+		if e.Severity == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterBySeverity returns the errors with the given severity
+func (s LexErrorSlice) FilterBySeverity(severity ErrorSeverity) LexErrorSlice {
+	var filtered LexErrorSlice
+	for _, e := range s {
+		if e.Severity == severity {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// First returns the first error, or nil if there are none
+func (s LexErrorSlice) First() *LexError {
+	if len(s) == 0 {
+		return nil
+	}
+	return s[0]
+}
+
+// markErrorEnds ends the span of each error that has none at the last
+// character the lexer has consumed, for errors raised while scanning a token
+func (l *Lexer) markErrorEnds(errs []*LexError) {
+	if l.position == 0 {
+		return
+	}
+	_, size := utf8.DecodeLastRuneInString(l.input[:l.position])
+	line, column := l.offsetPosition(l.position - size)
+
+	for _, e := range errs {
+		if e.EndLine != 0 {
+			continue
+		}
+		if line < e.Line || (line == e.Line && column < e.Column) {
+			e.EndLine, e.EndColumn = e.Line, e.Column
+			continue
+		}
+		e.EndLine, e.EndColumn = line, column
+	}
+}
+
+// FormatErrors renders errors with the source line each one starts on and
+// an underline beneath the text it covers, for example:
+//
+//	lexical error at line 1, column 9: unterminated string literal
+//	  let s = "open
+//	          ^~~~~
+//
+// A span that continues onto later lines is underlined to the end of its
+// first line. Errors without a span get a single caret.
+func FormatErrors(source string, errs []*LexError) string {
+	lines := strings.Split(source, "\n")
+	var b strings.Builder
+
+	for _, e := range errs {
+		b.WriteString(e.Error())
+		b.WriteByte('\n')
+		if e.Line < 1 || e.Line > len(lines) || e.Column < 1 {
+			continue
+		}
+
+		text := []rune(strings.TrimSuffix(lines[e.Line-1], "\r"))
+		start := min(e.Column-1, len(text))
+		end := start
+		switch {
+		case e.EndLine > e.Line:
+			end = len(text) - 1
+		case e.EndLine == e.Line && e.EndColumn > e.Column:
+			end = min(e.EndColumn-1, len(text)-1)
+		}
+
+		// Keep tabs in the indent so the underline lines up with the text
+		indent := make([]rune, start)
+		for i := range indent {
+			indent[i] = ' '
+			if text[i] == '\t' {
+				indent[i] = '\t'
+			}
+		}
+
+		b.WriteString("  " + string(text) + "\n")
+		b.WriteString("  " + string(indent) + "^" + strings.Repeat("~", max(end-start, 0)) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,270 @@
+package golexer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Test that lexer errors carry a code
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		input string
+		code  int
+	}{
+		{"§", CodeUnexpectedChar},
+		{"&", CodeUnexpectedChar},
+		{"0xZZ", CodeInvalidNumber},
+		{"123abc", CodeInvalidNumber},
+		{`"\q"`, CodeInvalidEscape},
+		{`"open`, CodeUnterminatedString},
+		{"`open", CodeUnterminatedString},
+		{"'a", CodeUnterminatedChar},
+		{"/* open", CodeUnterminatedComment},
+	}
+
+	for _, tt := range tests {
+		_, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) == 0 {
+			t.Errorf("Input %q: expected an error", tt.input)
+			continue
+		}
+		if errors[0].Code != tt.code {
+			t.Errorf("Input %q: expected code %d, got %d", tt.input, tt.code, errors[0].Code)
+		}
+		if errors[0].Severity != SeverityError {
+			t.Errorf("Input %q: expected error severity", tt.input)
+		}
+	}
+
+	_, errors := NewIndentLexer(NewLexer("a\n \tb")).TokenizeAll()
+	if len(errors) != 1 || errors[0].Code != CodeInvalidIndentation {
+		t.Errorf("Expected an indentation error code, got %v", errors)
+	}
+}
+
+// Test filtering collected errors
+func TestGetErrorsWhere(t *testing.T) {
+	l := NewLexer("x = §;\ny = 0xZZ;\nz = 1abc")
+	l.TokenizeAll()
+	l.errors = append(l.errors, &LexError{Message: "style", Line: 2, Column: 1, Code: CodeInvalidNumber, Severity: SeverityWarning})
+
+	if errs := l.GetErrorsOnLine(2); len(errs) != 2 {
+		t.Errorf("Expected 2 errors on line 2, got %v", errs)
+	}
+	if errs := l.GetErrorsOnLine(4); len(errs) != 0 {
+		t.Errorf("Expected no errors on line 4, got %v", errs)
+	}
+	if errs := l.GetErrorsWithCode(CodeInvalidNumber); len(errs) != 3 {
+		t.Errorf("Expected 3 number errors, got %v", errs)
+	}
+	if errs := l.GetErrorsWithCode(CodeUnexpectedChar); len(errs) != 1 || errs[0].Line != 1 {
+		t.Errorf("Expected 1 unexpected character error, got %v", errs)
+	}
+	if warnings := l.GetWarnings(); len(warnings) != 1 || warnings[0].Message != "style" {
+		t.Errorf("Expected 1 warning, got %v", warnings)
+	}
+	errs := l.GetErrorsWhere(func(e *LexError) bool { return e.Severity == SeverityError && e.Line > 1 })
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors after line 1, got %v", errs)
+	}
+
+	collected := l.GetErrors()
+	l.ClearErrors()
+	if l.HasErrors() || len(l.GetErrors()) != 0 {
+		t.Errorf("Expected no errors after ClearErrors")
+	}
+	if len(collected) != 4 {
+		t.Errorf("Expected previously returned errors to be kept, got %d", len(collected))
+	}
+}
+
+// Test matching collected errors against sentinels
+func TestLexErrorIs(t *testing.T) {
+	tests := []struct {
+		input    string
+		sentinel *LexError
+	}{
+		{`"open`, ErrUnterminatedString},
+		{"'a", ErrUnterminatedChar},
+		{`"\q"`, ErrInvalidEscape},
+		{"0b102", ErrInvalidNumber},
+		{"§", ErrUnexpectedChar},
+		{"/* open", ErrUnterminatedComment},
+	}
+
+	for _, tt := range tests {
+		_, lexErrors := NewLexer(tt.input).TokenizeAll()
+		if len(lexErrors) == 0 {
+			t.Errorf("Input %q: expected an error", tt.input)
+			continue
+		}
+		var err error = lexErrors[0]
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("Input %q: expected errors.Is to match %q", tt.input, tt.sentinel.Message)
+		}
+		if errors.Is(err, ErrUnterminatedComment) != (tt.sentinel == ErrUnterminatedComment) {
+			t.Errorf("Input %q: unexpected match against ErrUnterminatedComment", tt.input)
+		}
+	}
+
+	// Sentinels also match through wrapping and ValidateSyntax
+	err := fmt.Errorf("compiling: %w", ValidateSyntax(`let s = "open`))
+	if !errors.Is(err, ErrUnterminatedString) {
+		t.Errorf("Expected wrapped error to match ErrUnterminatedString")
+	}
+
+	var lexErr *LexError
+	if !errors.As(err, &lexErr) || lexErr.Code != CodeUnterminatedString {
+		t.Errorf("Expected errors.As to find the LexError, got %v", lexErr)
+	}
+
+	if errors.Is(&LexError{Message: "no code"}, &LexError{Message: "other"}) {
+		t.Errorf("Expected errors without codes not to match each other")
+	}
+}
+
+// Test LexErrorSlice formatting and queries
+func TestLexErrorSlice(t *testing.T) {
+	_, errs := NewLexer("§ 0xZZ \"open").TokenizeAll()
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %d", len(errs))
+	}
+
+	msg := errs.Error()
+	if strings.Count(msg, "\n") != 2 {
+		t.Errorf("Expected one line per error, got %q", msg)
+	}
+	for _, e := range errs {
+		if !strings.Contains(msg, e.Message) {
+			t.Errorf("Expected %q in %q", e.Message, msg)
+		}
+	}
+
+	if !errs.HasCode(CodeInvalidNumber) || errs.HasCode(CodeUnterminatedComment) {
+		t.Errorf("Unexpected HasCode results")
+	}
+	if !errs.HasSeverity(SeverityError) || errs.HasSeverity(SeverityWarning) {
+		t.Errorf("Unexpected HasSeverity results")
+	}
+	if first := errs.First(); first == nil || first.Code != CodeUnexpectedChar {
+		t.Errorf("Expected the unexpected character error first, got %v", first)
+	}
+
+	errs = append(errs, &LexError{Message: "style", Severity: SeverityWarning})
+	if warnings := errs.FilterBySeverity(SeverityWarning); len(warnings) != 1 {
+		t.Errorf("Expected 1 warning, got %v", warnings)
+	}
+	if errors := errs.FilterBySeverity(SeverityError); len(errors) != 3 {
+		t.Errorf("Expected 3 errors, got %v", errors)
+	}
+
+	var empty LexErrorSlice
+	if empty.First() != nil || empty.Error() != "" {
+		t.Errorf("Expected an empty slice to have no first error or message")
+	}
+
+	// Still usable as a plain slice
+	var plain []*LexError = errs
+	if len(plain) != 4 {
+		t.Errorf("Expected 4 errors, got %d", len(plain))
+	}
+}
+
+// Test errors carry the lexer's source name
+func TestLexErrorSourceFile(t *testing.T) {
+	l := NewLexer("let s = \"open")
+	l.SetSourceName("main.my")
+	l.TokenizeAll()
+
+	errs := l.GetErrors()
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, got %v", errs)
+	}
+	if errs[0].SourceFile != "main.my" {
+		t.Errorf("Expected SourceFile %q, got %q", "main.my", errs[0].SourceFile)
+	}
+	if expected := "main.my:1:9: unterminated string literal"; errs[0].Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, errs[0].Error())
+	}
+
+	_, errs = NewLexer("x §", WithSourceName("other.my")).TokenizeAll()
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "other.my:1:3: ") {
+		t.Errorf("Expected an error prefixed with other.my:1:3, got %v", errs)
+	}
+
+	_, errs = NewLexer("x §").TokenizeAll()
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "lexical error at line 1, column 3: ") {
+		t.Errorf("Expected the plain format without a source name, got %v", errs)
+	}
+
+	e := (&LexError{Message: "bad", Line: 2, Column: 4}).SetSourceFile("lib.my")
+	if e.Error() != "lib.my:2:4: bad" {
+		t.Errorf("Expected SetSourceFile to set the name, got %q", e.Error())
+	}
+}
+
+// Test error spans and their formatting
+func TestFormatErrors(t *testing.T) {
+	tests := []struct {
+		input     string
+		endLine   int
+		endColumn int
+		expected  string
+	}{
+		{
+			"let s = \"hello",
+			1, 14,
+			"lexical error at line 1, column 9: unterminated string literal\n" +
+				"  let s = \"hello\n" +
+				"          ^~~~~~\n",
+		},
+		{
+			"let s = \"hello\nworld",
+			2, 5,
+			"lexical error at line 1, column 9: unterminated string literal\n" +
+				"  let s = \"hello\n" +
+				"          ^~~~~~\n",
+		},
+		{
+			"x § y",
+			1, 3,
+			"lexical error at line 1, column 3: unexpected character '§' (Unicode: U+00A7)\n" +
+				"  x § y\n" +
+				"    ^\n",
+		},
+		{
+			"\tlet n = 123abc;",
+			1, 15,
+			"lexical error at line 1, column 13: invalid number: numbers cannot be followed by letters\n" +
+				"  \tlet n = 123abc;\n" +
+				"  \t           ^~~\n",
+		},
+	}
+
+	for _, tt := range tests {
+		_, errs := NewLexer(tt.input).TokenizeAll()
+		if len(errs) != 1 {
+			t.Errorf("Input %q: expected one error, got %v", tt.input, errs)
+			continue
+		}
+		if errs[0].EndLine != tt.endLine || errs[0].EndColumn != tt.endColumn {
+			t.Errorf("Input %q: expected the span to end at %d:%d, got %d:%d", tt.input, tt.endLine, tt.endColumn, errs[0].EndLine, errs[0].EndColumn)
+		}
+		if got := FormatErrors(tt.input, errs); got != tt.expected {
+			t.Errorf("Input %q: expected\n%s\ngot\n%s", tt.input, tt.expected, got)
+		}
+	}
+
+	// Errors without a span, or outside the source, still format
+	errs := []*LexError{
+		{Message: "no span", Line: 1, Column: 2},
+		{Message: "past the end", Line: 5, Column: 1},
+	}
+	expected := "lexical error at line 1, column 2: no span\n  ab\n   ^\n" +
+		"lexical error at line 5, column 1: past the end\n"
+	if got := FormatErrors("ab", errs); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+}
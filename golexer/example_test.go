@@ -0,0 +1,111 @@
+package golexer
+
+import (
+	"fmt"
+	"os"
+)
+
+// writeExampleConfig writes a config adding the keyword "unless" to a
+// temporary file and returns its path
+func writeExampleConfig() string {
+	f, err := os.CreateTemp("", "golexer-example-*.json")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(`{"additionalKeywords": {"unless": "UNLESS"}}`); err != nil {
+		panic(err)
+	}
+	return f.Name()
+}
+
+func ExampleNewLexer() {
+	l := NewLexer("let total = price * 2;")
+	for tok := l.NextToken(); tok.Type != EOF; tok = l.NextToken() {
+		fmt.Println(tok.Type, tok.Literal)
+	}
+	// Output:
+	// LET let
+	// IDENT total
+	// ASSIGN =
+	// IDENT price
+	// MULTIPLY *
+	// NUMBER 2
+	// SEMICOLON ;
+}
+
+func ExampleLexer_NextToken() {
+	l := NewLexer("if (x >= 10) {\n  return \"big\";\n}")
+	for {
+		tok := l.NextToken()
+		if tok.Type == EOF {
+			break
+		}
+		fmt.Println(tok)
+	}
+	// Output:
+	// [IF "if" 1:1]
+	// [LPAREN "(" 1:4]
+	// [IDENT "x" 1:5]
+	// [GREATER_THAN_EQL ">=" 1:7]
+	// [NUMBER "10" 1:10]
+	// [RPAREN ")" 1:12]
+	// [LBRACE "{" 1:14]
+	// [RETURN "return" 2:3]
+	// [STRING "big" 2:10]
+	// [SEMICOLON ";" 2:15]
+	// [RBRACE "}" 3:1]
+}
+
+func ExampleLexer_TokenizeAll() {
+	tokens, errors := NewLexer("fn add(a, b) { return a + b; }").TokenizeAll()
+	fmt.Println(len(tokens), "tokens,", len(errors), "errors")
+	fmt.Println(tokens[0], tokens[len(tokens)-1])
+	// Output:
+	// 14 tokens, 0 errors
+	// [FN "fn" 1:1] [RBRACE "}" 1:30]
+}
+
+func ExampleLexer_GetErrors() {
+	l := NewLexer("let x = 123abc;\nlet s = \"open")
+	l.TokenizeAll()
+	for _, err := range l.GetErrors() {
+		fmt.Println(err)
+	}
+	// Output:
+	// lexical error at line 1, column 12: invalid number: numbers cannot be followed by letters
+	// lexical error at line 2, column 9: unterminated string literal
+}
+
+func ExampleNewLexerWithConfig() {
+	path := writeExampleConfig()
+	defer os.Remove(path)
+
+	l := NewLexerWithConfig("unless done", path)
+	for tok := l.NextToken(); tok.Type != EOF; tok = l.NextToken() {
+		fmt.Println(tok.Type, tok.Literal)
+	}
+
+	// The config applies to that lexer only
+	fmt.Println(NewLexer("unless").NextToken().Type)
+	// Output:
+	// UNLESS unless
+	// IDENT done
+	// IDENT
+}
+
+func ExampleLoadConfig() {
+	path := writeExampleConfig()
+	defer os.Remove(path)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(config.AdditionalKeywords["unless"])
+	fmt.Println(NewLexer("unless", WithConfig(config)).NextToken().Type)
+	// Output:
+	// UNLESS
+	// UNLESS
+}
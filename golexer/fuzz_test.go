@@ -0,0 +1,80 @@
+//go:build gofuzz
+
+package golexer_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codetesla51/golexer/golexer"
+)
+
+// Run with: go test -tags gofuzz -fuzz=FuzzTokenize ./golexer
+func FuzzTokenize(f *testing.F) {
+	seeds := []string{
+		"",
+		"x",
+		"\"",
+		"18446744073709551615",
+		"let const fn if else while for return break continue true false null",
+		"42 3.14 1e10 2.5e-3 1E+5 0x10 0b1010 0o755 0777",
+		`"hello" "escaped\"quote" "newline\ntest" "\x41" "${a + b}"`,
+		`'a' '\n' '\t' '\\' '\''`,
+		"let x = 5; // line comment\n/* block comment */ let y = 10;",
+		"= + - * / == != < <= > >= += -= *= /= && || ! -> |> ++ --",
+		"123abc invalid#name ' '\\x' \"unterminated \"escape\\q\" & |",
+		"`raw\nstring`",
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		lexer := golexer.NewLexer(string(data))
+		tokens, errors := lexer.TokenizeAll()
+
+		for _, tok := range tokens {
+			if tok.Offset < 0 || tok.Offset > len(data) {
+				t.Fatalf("token %q has offset %d outside input of length %d", tok.Literal, tok.Offset, len(data))
+			}
+			if tok.EndOffset < tok.Offset || tok.EndOffset > len(data) {
+				t.Fatalf("token %q has end offset %d outside %d..%d", tok.Literal, tok.EndOffset, tok.Offset, len(data))
+			}
+		}
+
+		if len(errors) > 0 {
+			return
+		}
+
+		// Literals of simple tokens must lex cleanly on their own
+		for _, tok := range tokens {
+			if !tok.IsIdentifier() && !tok.IsKeyword() && !tok.IsOperator() && tok.Type != golexer.NUMBER {
+				continue
+			}
+			if errs := golexer.ScanOnly(tok.Literal); len(errs) > 0 {
+				t.Fatalf("re-lexing %s literal %q produced errors: %v", tok.Type, tok.Literal, errs)
+			}
+		}
+	})
+}
+
+// Run with: go test -tags gofuzz -fuzz=FuzzLoadConfig ./golexer
+func FuzzLoadConfig(f *testing.F) {
+	f.Add([]byte(`{"additionalKeywords": {"unless": "UNLESS"}}`))
+	f.Add([]byte(`{"additionalOperators": {"**": "POWER"}, "additionalPunctuation": {"@": "AT"}}`))
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`not json`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := golexer.LoadConfig(path)
+		if err == nil && config == nil {
+			t.Fatalf("LoadConfig returned nil config without error")
+		}
+	})
+}
@@ -0,0 +1,79 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Token Grep
+Finds tokens, and runs of consecutive tokens, that match simple patterns
+on type, literal and line, for search tools built on the token stream.
+*/
+
+package golexer
+
+import "strings"
+
+// TokenPattern matches tokens by type, literal substring and line. Nil
+// fields are wildcards, so the zero pattern matches every token.
+type TokenPattern struct {
+	Type            *TokenType
+	LiteralContains *string
+	Line            *int
+}
+
+// matches reports whether tok satisfies every non-nil field of the pattern
+func (p TokenPattern) matches(tok Token) bool {
+	if p.Type != nil && tok.Type != *p.Type {
+		return false
+	}
+	if p.LiteralContains != nil && !strings.Contains(tok.Literal, *p.LiteralContains) {
+		return false
+	}
+	if p.Line != nil && tok.Line != *p.Line {
+		return false
+	}
+	return true
+}
+
+// Grep returns the tokens that match the pattern, in order
+func Grep(tokens []Token, pat TokenPattern) []Token {
+	var matched []Token
+	for _, tok := range tokens {
+		if pat.matches(tok) {
+			matched = append(matched, tok)
+		}
+	}
+	return matched
+}
+
+// GrepSequence returns every run of consecutive tokens matching pats one
+// for one. The search resumes after each match, so matches do not overlap
+// and the leftmost of two overlapping candidates wins. An empty pats
+// matches nothing.
+func GrepSequence(tokens []Token, pats []TokenPattern) [][]Token {
+	if len(pats) == 0 {
+		return nil
+	}
+
+	var matches [][]Token
+	for i := 0; i+len(pats) <= len(tokens); {
+		if !sequenceMatches(tokens[i:], pats) {
+			i++
+			continue
+		}
+		end := i + len(pats)
+		matches = append(matches, tokens[i:end:end])
+		i = end
+	}
+	return matches
+}
+
+// sequenceMatches reports whether tokens starts with a run matching pats
+func sequenceMatches(tokens []Token, pats []TokenPattern) bool {
+	for j, pat := range pats {
+		if !pat.matches(tokens[j]) {
+			return false
+		}
+	}
+	return true
+}
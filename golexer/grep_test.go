@@ -0,0 +1,77 @@
+package golexer
+
+import "testing"
+
+// Test matching single tokens against patterns
+func TestGrep(t *testing.T) {
+	tokens, _ := NewLexer("let total = 1;\nlet count = total + 2;").TokenizeAll()
+
+	ident, let := IDENT, LET
+	tot, line := "tot", 2
+	tests := []struct {
+		name     string
+		pat      TokenPattern
+		expected []string
+	}{
+		{"type", TokenPattern{Type: &let}, []string{"let", "let"}},
+		{"literal", TokenPattern{LiteralContains: &tot}, []string{"total", "total"}},
+		{"type and line", TokenPattern{Type: &ident, Line: &line}, []string{"count", "total"}},
+		{"wildcard", TokenPattern{}, nil},
+	}
+
+	for _, tt := range tests {
+		matched := Grep(tokens, tt.pat)
+		if tt.expected == nil {
+			if len(matched) != len(tokens) {
+				t.Errorf("%s: expected all %d tokens, got %d", tt.name, len(tokens), len(matched))
+			}
+			continue
+		}
+		if len(matched) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, matched)
+			continue
+		}
+		for i, literal := range tt.expected {
+			if matched[i].Literal != literal {
+				t.Errorf("%s[%d]: expected %q, got %q", tt.name, i, literal, matched[i].Literal)
+			}
+		}
+	}
+}
+
+// Test finding runs of tokens such as function declarations
+func TestGrepSequence(t *testing.T) {
+	input := "fn add(a, b) { return a + b; }\nlet f = fn(x) { x };\nfn main() { add(1, 2); }"
+	tokens, _ := NewLexer(input).TokenizeAll()
+
+	fn, ident := FN, IDENT
+	decls := GrepSequence(tokens, []TokenPattern{{Type: &fn}, {Type: &ident}})
+	if len(decls) != 2 {
+		t.Fatalf("Expected 2 function declarations, got %v", decls)
+	}
+	for i, name := range []string{"add", "main"} {
+		if len(decls[i]) != 2 || decls[i][0].Type != FN || decls[i][1].Type != IDENT || decls[i][1].Literal != name {
+			t.Errorf("Declaration %d: expected FN IDENT %q, got %v", i, name, decls[i])
+		}
+	}
+
+	// Overlapping candidates: the leftmost match wins and the search resumes after it
+	tokens, _ = NewLexer("a b c d e").TokenizeAll()
+	pairs := GrepSequence(tokens, []TokenPattern{{Type: &ident}, {Type: &ident}})
+	if len(pairs) != 2 || pairs[0][0].Literal != "a" || pairs[1][0].Literal != "c" {
+		t.Errorf("Expected pairs starting at a and c, got %v", pairs)
+	}
+
+	// Appending to a match must not overwrite the tokens after it
+	_ = append(pairs[0], NewToken(NUMBER, "1", 1, 1))
+	if tokens[2].Literal != "c" {
+		t.Errorf("Expected appending to a match to leave the stream unchanged")
+	}
+
+	if got := GrepSequence(tokens, nil); got != nil {
+		t.Errorf("Expected no matches for an empty pattern, got %v", got)
+	}
+	if got := GrepSequence(tokens[:1], []TokenPattern{{}, {}}); got != nil {
+		t.Errorf("Expected no matches when the stream is shorter than the pattern, got %v", got)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Indentation Consistency Checking
+Lints the indentation of a token stream produced with WithEmitWhitespace.
+Unlike IndentLexer, which turns indentation into INDENT and DEDENT tokens,
+this only reports style problems: tabs mixed with spaces, indents that
+are not a multiple of the indent size, and blocks indented by more than
+one level at once.
+*/
+
+package golexer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckIndentation reports inconsistent indentation in tokens from a lexer
+// created with WithEmitWhitespace. Tabs advance to the next multiple of
+// tabWidth. The indent size is taken from the first indented line, and
+// only increases are limited to one level since closing several blocks
+// at once is normal. The first line and blank lines are never reported.
+// Problems are returned as warnings.
+func CheckIndentation(tokens []Token, tabWidth int) LexErrorSlice {
+	if tabWidth < 1 {
+		tabWidth = 1
+	}
+
+	var errs LexErrorSlice
+	report := func(line int, format string, args ...interface{}) {
+		errs = append(errs, &LexError{
+			Message:  fmt.Sprintf(format, args...),
+			Line:     line,
+			Column:   1,
+			Code:     CodeInvalidIndentation,
+			Severity: SeverityWarning,
+		})
+	}
+
+	unit, previous := 0, 0
+	for i, tok := range tokens {
+		if tok.Type != WHITESPACE || i+1 >= len(tokens) {
+			continue
+		}
+
+		newline := strings.LastIndexByte(tok.Literal, '\n')
+		if newline < 0 {
+			// Leading whitespace on the first line sets the starting level
+			if i == 0 {
+				previous = indentationWidth(tok.Literal, tabWidth)
+			}
+			continue
+		}
+
+		indent := strings.TrimPrefix(tok.Literal[newline+1:], "\r")
+		line := tokens[i+1].Line
+
+		if strings.ContainsRune(indent, ' ') && strings.ContainsRune(indent, '\t') {
+			report(line, "inconsistent use of tabs and spaces in indentation")
+		}
+
+		width := indentationWidth(indent, tabWidth)
+		if width > 0 && unit == 0 {
+			unit = width - previous
+			if unit <= 0 {
+				unit = width
+			}
+		}
+
+		if unit > 0 {
+			if width%unit != 0 {
+				report(line, "indentation of %d is not a multiple of %d", width, unit)
+			} else if width > previous+unit {
+				report(line, "indentation increased by more than one level")
+			}
+		}
+		previous = width
+	}
+
+	return errs
+}
+
+// indentationWidth returns the width of leading spaces and tabs
+func indentationWidth(indent string, tabWidth int) int {
+	width := 0
+	for _, ch := range indent {
+		switch ch {
+		case ' ':
+			width++
+		case '\t':
+			width += tabWidth - width%tabWidth
+		default:
+			return width
+		}
+	}
+	return width
+}
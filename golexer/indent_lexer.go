@@ -0,0 +1,133 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Indentation-Aware Lexing
+Wraps the core lexer for languages with significant indentation
+(Python-style blocks). Leading whitespace at the start of each line
+is compared against a stack of open indentation levels, and INDENT
+or DEDENT tokens are emitted whenever a block opens or closes.
+*/
+
+package golexer
+
+import "strings"
+
+// IndentLexer wraps a Lexer and emits INDENT and DEDENT tokens
+// around indented blocks
+type IndentLexer struct {
+	lexer    *Lexer
+	indents  []int
+	pending  []Token
+	lines    []string
+	lastLine int
+}
+
+// NewIndentLexer creates an indentation-aware lexer around l
+func NewIndentLexer(l *Lexer) *IndentLexer {
+	return &IndentLexer{
+		lexer:   l,
+		indents: []int{0},
+		lines:   strings.Split(l.input, "\n"),
+	}
+}
+
+// NextToken returns the next token, inserting INDENT and DEDENT tokens
+// whenever the indentation of a new line changes
+func (il *IndentLexer) NextToken() Token {
+	if len(il.pending) > 0 {
+		tok := il.pending[0]
+		il.pending = il.pending[1:]
+		return tok
+	}
+
+	tok := il.lexer.NextToken()
+
+	if tok.Type == EOF {
+		// Close every block still open at end of input
+		for len(il.indents) > 1 {
+			il.indents = il.indents[:len(il.indents)-1]
+			il.pending = append(il.pending, Token{Type: DEDENT, Line: tok.Line, Column: tok.Column})
+		}
+		il.pending = append(il.pending, tok)
+		return il.NextToken()
+	}
+
+	if tok.Line == il.lastLine {
+		return tok
+	}
+	il.lastLine = tok.Line
+
+	width := il.indentWidth(tok.Line)
+	top := il.indents[len(il.indents)-1]
+
+	if width > top {
+		il.indents = append(il.indents, width)
+		il.pending = append(il.pending, Token{Type: INDENT, Line: tok.Line, Column: 1})
+	} else if width < top {
+		for len(il.indents) > 1 && il.indents[len(il.indents)-1] > width {
+			il.indents = il.indents[:len(il.indents)-1]
+			il.pending = append(il.pending, Token{Type: DEDENT, Line: tok.Line, Column: 1})
+		}
+		if il.indents[len(il.indents)-1] != width {
+			il.addError(tok.Line, "unindent does not match any outer indentation level")
+		}
+	}
+
+	il.pending = append(il.pending, tok)
+	return il.NextToken()
+}
+
+// TokenizeAll returns all tokens including INDENT and DEDENT, along with any errors
+func (il *IndentLexer) TokenizeAll() ([]Token, LexErrorSlice) {
+	var tokens []Token
+
+	for {
+		tok := il.NextToken()
+		if tok.Type == EOF {
+			break
+		}
+		tokens = append(tokens, tok)
+	}
+
+	return tokens, il.lexer.errors
+}
+
+// indentWidth returns the width of the leading whitespace on a line,
+// reporting an error if tabs and spaces are mixed
+func (il *IndentLexer) indentWidth(line int) int {
+	if line < 1 || line > len(il.lines) {
+		return 0
+	}
+
+	text := il.lines[line-1]
+	width := 0
+	hasTab, hasSpace := false, false
+	for _, ch := range text {
+		if ch == '\t' {
+			hasTab = true
+		} else if ch == ' ' {
+			hasSpace = true
+		} else {
+			break
+		}
+		width++
+	}
+
+	if hasTab && hasSpace {
+		il.addError(line, "inconsistent use of tabs and spaces in indentation")
+	}
+	return width
+}
+
+func (il *IndentLexer) addError(line int, message string) {
+	il.lexer.errors = append(il.lexer.errors, &LexError{
+		Message:    message,
+		Line:       line,
+		Column:     1,
+		Code:       CodeInvalidIndentation,
+		SourceFile: il.lexer.sourceName,
+	})
+}
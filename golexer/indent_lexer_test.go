@@ -0,0 +1,75 @@
+package golexer
+
+import (
+	"testing"
+)
+
+// Test INDENT/DEDENT emission across three nesting levels
+func TestIndentLexer(t *testing.T) {
+	input := "fn main:\n" +
+		"    if x:\n" +
+		"        while y:\n" +
+		"            z\n" +
+		"        w\n" +
+		"v\n"
+
+	il := NewIndentLexer(NewLexer(input))
+	tokens, errors := il.TokenizeAll()
+
+	if len(errors) != 0 {
+		t.Fatalf("Expected no errors, got %v", errors)
+	}
+
+	expected := []TokenType{
+		FN, IDENT, COLON,
+		INDENT, IF, IDENT, COLON,
+		INDENT, WHILE, IDENT, COLON,
+		INDENT, IDENT,
+		DEDENT, IDENT,
+		DEDENT, DEDENT, IDENT,
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %v", len(expected), len(tokens), tokens)
+	}
+	for i, tt := range expected {
+		if tokens[i].Type != tt {
+			t.Errorf("Token %d: expected %s, got %s (literal: %q)", i, tt, tokens[i].Type, tokens[i].Literal)
+		}
+	}
+}
+
+// Test that open blocks are closed at end of input
+func TestIndentLexerDedentAtEOF(t *testing.T) {
+	il := NewIndentLexer(NewLexer("a\n  b\n    c"))
+
+	expected := []TokenType{IDENT, INDENT, IDENT, INDENT, IDENT, DEDENT, DEDENT, EOF}
+	for i, tt := range expected {
+		tok := il.NextToken()
+		if tok.Type != tt {
+			t.Errorf("Token %d: expected %s, got %s", i, tt, tok.Type)
+		}
+	}
+}
+
+// Test indentation errors
+func TestIndentLexerErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{"a\n \tb", 2},       // tabs and spaces mixed
+		{"a\n    b\n  c", 3}, // dedent to unknown level
+	}
+
+	for _, tt := range tests {
+		_, errors := NewIndentLexer(NewLexer(tt.input)).TokenizeAll()
+		if len(errors) != 1 {
+			t.Errorf("Input %q: expected 1 error, got %d", tt.input, len(errors))
+			continue
+		}
+		if errors[0].Line != tt.line {
+			t.Errorf("Input %q: expected error on line %d, got %d", tt.input, tt.line, errors[0].Line)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package golexer
+
+import "testing"
+
+// Test indentation problems are reported on the right lines
+func TestCheckIndentation(t *testing.T) {
+	input := "if a {\n" +
+		"    b\n" + // sets the indent size to 4
+		"      c\n" + // not a multiple of 4
+		"\t  d\n" + // mixed, and 6 is not a multiple of 4
+		"\n" +
+		"            e\n" + // jumps from 6 to 12
+		"f\n" +
+		"        g\n" + // two levels at once
+		"    h\n" +
+		"}"
+	tokens, _ := NewLexer(input, WithEmitWhitespace(true)).TokenizeAll()
+	errs := CheckIndentation(tokens, 4)
+
+	expected := []struct {
+		line    int
+		message string
+	}{
+		{3, "indentation of 6 is not a multiple of 4"},
+		{4, "inconsistent use of tabs and spaces in indentation"},
+		{4, "indentation of 6 is not a multiple of 4"},
+		{6, "indentation increased by more than one level"},
+		{8, "indentation increased by more than one level"},
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), errs)
+	}
+	for i, want := range expected {
+		if errs[i].Line != want.line || errs[i].Message != want.message {
+			t.Errorf("Error %d: expected %q on line %d, got %q on line %d", i, want.message, want.line, errs[i].Message, errs[i].Line)
+		}
+		if errs[i].Code != CodeInvalidIndentation || errs[i].Severity != SeverityWarning {
+			t.Errorf("Error %d: expected an indentation warning, got %v", i, errs[i])
+		}
+	}
+}
+
+// Test consistent indentation, blank lines and the first line are accepted
+func TestCheckIndentationClean(t *testing.T) {
+	inputs := []string{
+		"a\n\tb\n\t\tc\n\n\n\td\ne",
+		"  a\n    b\n  c\n",
+		"a\n  b\n\n\n  c\n      \n  d",
+		"",
+	}
+
+	for _, input := range inputs {
+		tokens, _ := NewLexer(input, WithEmitWhitespace(true)).TokenizeAll()
+		if errs := CheckIndentation(tokens, 4); len(errs) != 0 {
+			t.Errorf("Input %q: expected no errors, got %v", input, errs)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Lexer State Inspection
+Exposes a read-only snapshot of the lexer's internal cursor for
+debugging custom grammars. Taking a snapshot never advances the lexer.
+*/
+
+package golexer
+
+import "fmt"
+
+// inspectPreviewLength is the number of bytes of remaining input
+// included in a LexerState
+const inspectPreviewLength = 20
+
+// LexerState is a snapshot of the lexer's internal state
+type LexerState struct {
+	Position     int    // byte offset of the current character
+	ReadPosition int    // byte offset of the next character
+	Ch           rune   // current character, 0 at end of input
+	Line         int    // line of the current character
+	Column       int    // column of the current character
+	ErrorCount   int    // number of errors reported so far
+	Buffered     int    // tokens read ahead but not yet returned
+	Remaining    string // up to the next 20 bytes of input, starting at Ch
+}
+
+// Inspect returns a snapshot of the lexer's current state without
+// consuming any input
+func (l *Lexer) Inspect() LexerState {
+	state := LexerState{
+		Position:     l.position,
+		ReadPosition: l.readPosition,
+		Ch:           l.ch,
+		Line:         l.line,
+		Column:       l.column,
+		ErrorCount:   len(l.errors),
+		Buffered:     len(l.tokenBuffer),
+	}
+
+	if l.position < len(l.input) {
+		end := l.position + inspectPreviewLength
+		if end > len(l.input) {
+			end = len(l.input)
+		}
+		state.Remaining = l.input[l.position:end]
+	}
+
+	return state
+}
+
+// String formats the state for display
+func (s LexerState) String() string {
+	return fmt.Sprintf("pos=%d read=%d ch=%q line=%d col=%d errors=%d buffered=%d next=%q",
+		s.Position, s.ReadPosition, s.Ch, s.Line, s.Column, s.ErrorCount, s.Buffered, s.Remaining)
+}
+
+// Position returns the line, column and byte offset of the character under
+// the cursor. It is meant for debugging: the lexer reads one character
+// ahead, so between tokens the position is that of the character after
+// the last token rather than the start of the next one.
+func (l *Lexer) Position() (line, column, offset int) {
+	return l.line, l.column, l.position
+}
+
+// ProcessedInput returns the input before the cursor, for debugging
+func (l *Lexer) ProcessedInput() string {
+	if l.position >= len(l.input) {
+		return l.input
+	}
+	return l.input[:l.position]
+}
+
+// RemainingInput returns the input from the cursor onwards, for debugging
+func (l *Lexer) RemainingInput() string {
+	if l.position >= len(l.input) {
+		return ""
+	}
+	return l.input[l.position:]
+}
@@ -0,0 +1,121 @@
+package golexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test inspecting the lexer state between tokens
+func TestInspect(t *testing.T) {
+	l := NewLexer("let x = 5;\ny & z")
+
+	state := l.Inspect()
+	if state.Position != 0 || state.Ch != 'l' || state.Line != 1 || state.Column != 1 {
+		t.Errorf("Unexpected initial state: %s", state)
+	}
+	if state.Remaining != "let x = 5;\ny & z" {
+		t.Errorf("Expected full remaining input, got %q", state.Remaining)
+	}
+
+	l.NextToken() // let
+	state = l.Inspect()
+	if state.Position != 3 || state.ReadPosition != 4 || state.Ch != ' ' {
+		t.Errorf("Unexpected state after 'let': %s", state)
+	}
+
+	// Inspect must not consume input
+	if tok := l.NextToken(); tok.Type != IDENT || tok.Literal != "x" {
+		t.Errorf("Expected IDENT x after Inspect, got %s %q", tok.Type, tok.Literal)
+	}
+
+	for l.NextToken().Type != ILLEGAL {
+	}
+	state = l.Inspect()
+	if state.ErrorCount != 1 || state.Line != 2 {
+		t.Errorf("Expected 1 error on line 2, got %s", state)
+	}
+
+	l.TokenizeAll()
+	state = l.Inspect()
+	if state.Ch != 0 || state.Remaining != "" {
+		t.Errorf("Expected end of input, got %s", state)
+	}
+}
+
+// Test that the remaining input preview is truncated
+func TestInspectPreviewLength(t *testing.T) {
+	state := NewLexer(strings.Repeat("a", 50)).Inspect()
+	if len(state.Remaining) != inspectPreviewLength {
+		t.Errorf("Expected %d byte preview, got %d", inspectPreviewLength, len(state.Remaining))
+	}
+	if !strings.Contains(state.String(), "line=1 col=1") {
+		t.Errorf("Unexpected String output: %s", state)
+	}
+}
+
+// Test querying the cursor position between tokens
+func TestPosition(t *testing.T) {
+	input := "let x = 10;\nfoo"
+	l := NewLexer(input)
+
+	expected := []struct {
+		line, column, offset int
+	}{
+		{1, 1, 0},   // before any token
+		{1, 4, 3},   // after let
+		{1, 6, 5},   // after x
+		{1, 8, 7},   // after =
+		{1, 11, 10}, // after 10
+		{2, 0, 11},  // after ;, on the newline which already counts as line 2
+		{2, 4, 15},  // after foo
+	}
+
+	for i, want := range expected {
+		if i > 0 {
+			l.NextToken()
+		}
+		line, column, offset := l.Position()
+		if line != want.line || column != want.column || offset != want.offset {
+			t.Errorf("Step %d: expected %d:%d@%d, got %d:%d@%d", i, want.line, want.column, want.offset, line, column, offset)
+		}
+		if input[:offset]+l.RemainingInput() != input {
+			t.Errorf("Step %d: consumed and remaining input do not add up", i)
+		}
+	}
+
+	if l.RemainingInput() != "" {
+		t.Errorf("Expected no remaining input, got %q", l.RemainingInput())
+	}
+}
+
+// Test processed and remaining input always split the whole input
+func TestProcessedInput(t *testing.T) {
+	input := "let x = 42;"
+	l := NewLexer(input)
+
+	check := func(step string) {
+		if len(l.ProcessedInput())+len(l.RemainingInput()) != len(input) {
+			t.Errorf("%s: %q and %q do not cover the input", step, l.ProcessedInput(), l.RemainingInput())
+		}
+		if l.ProcessedInput()+l.RemainingInput() != input {
+			t.Errorf("%s: expected %q, got %q", step, input, l.ProcessedInput()+l.RemainingInput())
+		}
+	}
+
+	if l.ProcessedInput() != "" {
+		t.Errorf("Expected nothing processed before lexing, got %q", l.ProcessedInput())
+	}
+	check("start")
+
+	l.NextToken()
+	if l.ProcessedInput() != "let" {
+		t.Errorf("Expected %q processed after the first token, got %q", "let", l.ProcessedInput())
+	}
+	check("first token")
+
+	l.TokenizeAll()
+	if l.ProcessedInput() != input || l.RemainingInput() != "" {
+		t.Errorf("Expected all input processed, got %q and %q", l.ProcessedInput(), l.RemainingInput())
+	}
+	check("end")
+}
@@ -0,0 +1,64 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Keyword Index
+A sorted array of the keywords searched with binary search, kept in
+step with the keywords map. LookupIdent uses it when built with the
+keywordarray tag; otherwise it uses the map, which benchmarks faster
+for the built-in keyword set (see BenchmarkLookupIdent).
+*/
+
+package golexer
+
+import "sort"
+
+// keywordEntry is one keyword and the token type it produces
+type keywordEntry struct {
+	word    string
+	tokType TokenType
+}
+
+// keywordArray is a list of keywords sorted by word
+type keywordArray []keywordEntry
+
+// newKeywordArray builds a sorted keyword array from a keyword map
+func newKeywordArray(m map[string]TokenType) keywordArray {
+	a := make(keywordArray, 0, len(m))
+	for word, tokType := range m {
+		a = append(a, keywordEntry{word, tokType})
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].word < a[j].word })
+	return a
+}
+
+// Lookup returns the token type for a keyword using binary search
+func (a keywordArray) Lookup(s string) (TokenType, bool) {
+	lo, hi := 0, len(a)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if a[mid].word < s {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo < len(a) && a[lo].word == s {
+		return a[lo].tokType, true
+	}
+	return "", false
+}
+
+// keywordIndex mirrors the keywords map
+var keywordIndex keywordArray
+
+func init() {
+	rebuildKeywordIndex()
+}
+
+// rebuildKeywordIndex must be called after the keywords map changes
+func rebuildKeywordIndex() {
+	keywordIndex = newKeywordArray(keywords)
+}
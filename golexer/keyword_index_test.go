@@ -0,0 +1,56 @@
+package golexer
+
+import "testing"
+
+// Test the sorted keyword index agrees with the keywords map
+func TestKeywordArrayLookup(t *testing.T) {
+	index := newKeywordArray(keywords)
+
+	for word, expected := range keywords {
+		tokType, ok := index.Lookup(word)
+		if !ok || tokType != expected {
+			t.Errorf("Keyword %q: expected %s, got %s (found %v)", word, expected, tokType, ok)
+		}
+	}
+
+	for _, word := range []string{"", "x", "lets", "Let", "zzz", "aaa", "contin"} {
+		if tokType, ok := index.Lookup(word); ok {
+			t.Errorf("Input %q: expected no keyword, got %s", word, tokType)
+		}
+	}
+}
+
+// Test keywords added by a config are found by LookupIdent
+func TestKeywordIndexRebuiltByConfig(t *testing.T) {
+	restorePackageTables(t)
+	(&Config{AdditionalKeywords: map[string]string{"unless": "UNLESS"}}).MergeWithDefaults()
+
+	if tokType := LookupIdent("unless"); tokType != "UNLESS" {
+		t.Errorf("Expected UNLESS, got %s", tokType)
+	}
+	if tokType := LookupIdent("let"); tokType != LET {
+		t.Errorf("Expected LET, got %s", tokType)
+	}
+	if tokType, ok := keywordIndex.Lookup("unless"); !ok || tokType != "UNLESS" {
+		t.Errorf("Expected the index to hold UNLESS, got %s (found %v)", tokType, ok)
+	}
+}
+
+var lookupIdentInputs = []string{"let", "x", "return", "counter", "while", "_tmp", "try", "value"}
+
+func BenchmarkLookupIdent(b *testing.B) {
+	b.Run("map", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, s := range lookupIdentInputs {
+				_ = keywords[s]
+			}
+		}
+	})
+	b.Run("array", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, s := range lookupIdentInputs {
+				keywordIndex.Lookup(s)
+			}
+		}
+	})
+}
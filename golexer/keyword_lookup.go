@@ -0,0 +1,9 @@
+//go:build !keywordarray
+
+package golexer
+
+// lookupKeyword returns the token type for a keyword from the keywords map
+func lookupKeyword(s string) (TokenType, bool) {
+	tok, ok := keywords[s]
+	return tok, ok
+}
@@ -0,0 +1,8 @@
+//go:build keywordarray
+
+package golexer
+
+// lookupKeyword returns the token type for a keyword from the sorted index
+func lookupKeyword(s string) (TokenType, bool) {
+	return keywordIndex.Lookup(s)
+}
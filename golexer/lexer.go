@@ -26,39 +26,160 @@ for building IDEs, compilers, and language tools.
 package golexer
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
+	"unsafe"
 )
 
-// Operator defines a single or compound operator
+// Operator defines a single or compound operator. When several operators
+// match at the current position, the one with the highest Priority wins,
+// and among equal priorities the longest literal wins.
 type Operator struct {
 	Single       string
 	SingleType   TokenType
 	Compound     string
 	CompoundType TokenType
+	Priority     int
 }
 
-// operators defines all operators with their single and compound forms
+// operators defines all operators with their single and compound forms.
+// Each lexer scans with its own copy, taken when it is created.
 var operators = []Operator{
 
-	{"=", ASSIGN, "==", EQL},
-	{"+", PLUS, "+=", PLUS_ASSIGN},
-	{"-", MINUS, "-=", MINUS_ASSIGN},
-	{"*", MULTIPLY, "*=", MULTIPLY_ASSIGN},
-	{"/", DIVIDE, "/=", DIVIDE_ASSIGN},
-	{"%", MODULUS, "%=", MODULUS_ASSIGN},
-	{"!", BANG, "!=", NOT_EQL},
-	{"<", LESS_THAN, "<=", LESS_THAN_EQL},
-	{">", GREATER_THAN, ">=", GREATER_THAN_EQL},
-	{"&", "", "&&", AND}, // Single & is invalid
-	{"|", "", "||", OR},  // Single | is invalid
+	{"=", ASSIGN, "==", EQL, 0},
+	{"+", PLUS, "+=", PLUS_ASSIGN, 0},
+	{"-", MINUS, "-=", MINUS_ASSIGN, 0},
+	{"*", MULTIPLY, "*=", MULTIPLY_ASSIGN, 0},
+	{"/", DIVIDE, "/=", DIVIDE_ASSIGN, 0},
+	{"%", MODULUS, "%=", MODULUS_ASSIGN, 0},
+	{"!", BANG, "!=", NOT_EQL, 0},
+	{"<", LESS_THAN, "<=", LESS_THAN_EQL, 0},
+	{">", GREATER_THAN, ">=", GREATER_THAN_EQL, 0},
+	{"~", TILDE, "~=", TILDE_ASSIGN, 0},
+	{"&", "", "&&", AND, 0}, // Single & is invalid
+	{"|", "", "||", OR, 0},  // Single | is invalid
+	{"<=>", SPACESHIP, "", "", 0},
+	{"<<", LEFT_SHIFT, "<<=", LEFT_SHIFT_ASSIGN, 0},
+	{">>", RIGHT_SHIFT, ">>=", RIGHT_SHIFT_ASSIGN, 0},
+	{">>>", UNSIGNED_RIGHT_SHIFT, ">>>=", UNSIGNED_RIGHT_SHIFT_ASSIGN, 0},
+	{"=~", MATCH_ASSIGN, "", "", 0},
+	{"->", ARROW, "", "", 0},
+	{"|>", PIPE, "", "", 0},
+	{"++", INCREMENT, "", "", 0},
+	{"--", DECREMENT, "", "", 0},
+	{"?", QUESTION, "??", NULL_COALESCE, 0},
+	{"?.", OPT_CHAIN, "", "", 0},
+	{"^", CARET, "^=", XOR_ASSIGN, 0},
+}
+
+func init() {
+	SortOperators()
+	if warnings := validateOperatorTable(operators); len(warnings) != 0 {
+		panic("golexer: built-in operator table: " + strings.Join(warnings, "; "))
+	}
+}
+
+// operatorForm is one spelling of an operator together with its token
+// type and the priority of the operator it belongs to
+type operatorForm struct {
+	literal  string
+	tokType  TokenType
+	priority int
+}
+
+// forms returns the single and compound forms of an operator
+func (op Operator) forms() [2]operatorForm {
+	return [2]operatorForm{{op.Single, op.SingleType, op.Priority}, {op.Compound, op.CompoundType, op.Priority}}
+}
+
+// operatorOrder holds the forms in the operator table in the order
+// lexers try them. SortOperators rebuilds it.
+var operatorOrder []operatorForm
+
+// operatorLength returns the length of the longest form of an operator
+func operatorLength(op Operator) int {
+	if len(op.Compound) > len(op.Single) {
+		return len(op.Compound)
+	}
+	return len(op.Single)
+}
+
+// SortOperators orders the operator table by descending priority and then
+// by descending literal length, and sets the order in which lexers created
+// afterwards try its forms. It must be called again after operators are
+// appended to the table.
+func SortOperators() {
+	sortOperators(operators)
+	operatorOrder = matchOrder(operators)
+}
+
+// sortOperators orders an operator table in place like SortOperators
+func sortOperators(ops []Operator) {
+	sort.SliceStable(ops, func(i, j int) bool {
+		if ops[i].Priority != ops[j].Priority {
+			return ops[i].Priority > ops[j].Priority
+		}
+		return operatorLength(ops[i]) > operatorLength(ops[j])
+	})
+}
+
+// matchOrder returns the forms of an operator table sorted by descending
+// priority and then by descending length, so the first form that matches
+// the input is the one to use
+func matchOrder(ops []Operator) []operatorForm {
+	var order []operatorForm
+	for _, op := range ops {
+		for _, form := range op.forms() {
+			if form.literal != "" && form.tokType != "" {
+				order = append(order, form)
+			}
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].priority != order[j].priority {
+			return order[i].priority > order[j].priority
+		}
+		return len(order[i].literal) > len(order[j].literal)
+	})
+	return order
+}
+
+// validateOperatorTable returns a warning for every literal that is defined
+// with different token types at the same priority, since only one of them
+// can ever match
+func validateOperatorTable(ops []Operator) []string {
+	type key struct {
+		literal  string
+		priority int
+	}
+	seen := make(map[key]TokenType)
+	var warnings []string
 
+	for _, op := range ops {
+		for _, form := range op.forms() {
+			if form.literal == "" || form.tokType == "" {
+				continue
+			}
+			k := key{form.literal, op.Priority}
+			if prev, ok := seen[k]; ok && prev != form.tokType {
+				warnings = append(warnings, fmt.Sprintf("operator '%s' is ambiguous at priority %d: %s and %s", form.literal, op.Priority, prev, form.tokType))
+				continue
+			}
+			seen[k] = form.tokType
+		}
+	}
+	return warnings
 }
 
-// singleCharTokens maps single characters to their token types
+// singleCharTokens maps single characters to their token types. Each
+// lexer scans with its own copy, taken when it is created.
 var singleCharTokens = map[rune]TokenType{
 	'(': LPAREN,
 	')': RPAREN,
@@ -70,51 +191,208 @@ var singleCharTokens = map[rune]TokenType{
 	';': SEMICOLON,
 	':': COLON,
 	'.': DOT,
-	'?': QUESTION,
+	'@': AT,
 }
 
-// Lexer represents the lexical analyzer
+// Lexer represents the lexical analyzer. It reads its input one token at
+// a time and records errors as it goes. A Lexer is not safe for
+// concurrent use.
 type Lexer struct {
-	input        string
+	input          string
+	position       int
+	readPosition   int
+	ch             rune
+	line           int
+	column         int
+	errors         []*LexError
+	tokenBuffer    []Token
+	maxInputSize   int
+	multiWordOps   []MultiWordOperator
+	emitComments   bool
+	emitWhitespace bool
+	handlers       []TokenHandler
+	errorMode      ErrorMode
+	lineCount      int
+	allowDollar    bool
+	annotations    bool
+	emitNewlines   bool
+	atLineStart    bool // no token but whitespace and comments on this line yet
+	afterNewline   bool // a newline was passed since the last token
+	lineOffsets    []int
+	sourceName     string
+	profiler       Profiler
+	fstrings       []formatString // open format strings, innermost last
+	lineComment    bool           // "//" starts a line comment
+	commentChar    rune           // extra line comment starter, or 0
+	unicodeIdent   bool
+	atEOF          bool      // the cursor is past the end of input
+	lastType       TokenType // type of the last token scanned, not counting trivia
+	floatSuffixes  []string
+	intSuffixes    []string
+	separators     bool
+	seenTypes      []TokenType // distinct types returned, in first-seen order
+	seenTypeSet    map[TokenType]struct{}
+	cacheTokens    bool
+	tokenCache     []Token      // tokens returned so far, kept for TokensOnLine
+	cacheDone      bool         // EOF has been returned, so tokenCache is complete
+	heredoc        *heredocJump // body to skip at the end of this line
+
+	// operators and singleChars are copies of the package tables, so a
+	// config applied to this lexer does not change any other lexer.
+	// operatorOrder is the order tryOperator tries the operator forms in,
+	// replaced rather than modified when the operators change.
+	operators     []Operator
+	operatorOrder []operatorForm
+	singleChars   map[rune]TokenType
+
+	// operatorTypes holds the token types of operators from a config, which
+	// Token.IsOperator does not know about
+	operatorTypes map[TokenType]struct{}
+
+	// keywords holds the keywords from a config, looked up before the
+	// package table, and keywordTypes their token types
+	keywords     map[string]TokenType
+	keywordTypes map[TokenType]struct{}
+
+	// multiCharTokens holds punctuation longer than one character from a config
+	multiCharTokens map[string]TokenType
+
+	// stringDelimiters holds the string delimiter pairs from a config
+	stringDelimiters []StringDelimiterPair
+
+	// SyncTokens are the token types SkipToSync stops at
+	SyncTokens []TokenType
+}
+
+// formatString tracks an f-string being scanned. depth counts the open
+// braces of the current {...} expression, and is 0 while reading text.
+type formatString struct {
+	quote        rune
+	depth        int
+	line, column int
+}
+
+// cursor holds the scanning position so the lexer can look ahead and back out
+type cursor struct {
 	position     int
 	readPosition int
 	ch           rune
 	line         int
 	column       int
-	errors       []*LexError
-	tokenBuffer  []Token
+	atEOF        bool
+	heredoc      *heredocJump
+}
+
+// heredocJump records heredoc bodies already read ahead. When the lexer
+// moves past the newline at offset newline, it continues from resume,
+// after the last closing tag.
+type heredocJump struct {
+	newline int
+	resume  cursor
+}
+
+func (l *Lexer) saveCursor() cursor {
+	return cursor{l.position, l.readPosition, l.ch, l.line, l.column, l.atEOF, l.heredoc}
+}
+
+func (l *Lexer) restoreCursor(c cursor) {
+	l.position, l.readPosition, l.ch, l.line, l.column, l.atEOF = c.position, c.readPosition, c.ch, c.line, c.column, c.atEOF
+	l.heredoc = c.heredoc
 }
 
-// NewLexer creates a new lexer instance with the given input
-func NewLexer(input string) *Lexer {
+// defaultFloatSuffixes are the float type suffixes allowed unless
+// WithFloatSuffixes is given
+var defaultFloatSuffixes = []string{"f32", "f64", "d128"}
+
+// NewLexer creates a new lexer instance with the given input, applying
+// opts in order. Input over the configured size limit is reported as an
+// error and lexed as empty.
+func NewLexer(input string, opts ...Option) *Lexer {
 	l := &Lexer{
-		input:  input,
-		line:   1,
-		column: 0,
-		errors: make([]*LexError, 0),
+		input:         input,
+		line:          1,
+		column:        0,
+		errors:        make([]*LexError, 0),
+		annotations:   true,
+		atLineStart:   true,
+		lineComment:   true,
+		floatSuffixes: defaultFloatSuffixes,
+		operators:     append([]Operator(nil), operators...),
+		operatorOrder: operatorOrder,
+		singleChars:   make(map[rune]TokenType, len(singleCharTokens)),
+	}
+	for ch, tokType := range singleCharTokens {
+		l.singleChars[ch] = tokType
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	// Oversized input is dropped so the lexer only reports the limit and EOF
+	if l.maxInputSize > 0 && len(input) > l.maxInputSize {
+		l.input = ""
+		l.errors = append(l.errors, &LexError{
+			Message:    fmt.Sprintf("input size %d bytes exceeds maximum input size of %d bytes", len(input), l.maxInputSize),
+			Line:       1,
+			Column:     1,
+			Code:       CodeInputTooLarge,
+			SourceFile: l.sourceName,
+		})
 	}
+
+	l.lineCount = countLines(l.input)
 	l.readChar()
 	return l
 }
 
-// load config
-func NewLexerWithConfig(input, configFile string) *Lexer {
+// countLines returns the number of lines in input. A trailing newline
+// does not start a new line.
+func countLines(input string) int {
+	if input == "" {
+		return 0
+	}
+	n := strings.Count(input, "\n")
+	if !strings.HasSuffix(input, "\n") {
+		n++
+	}
+	return n
+}
+
+// NewLexerFromBytes creates a lexer over b without copying it. The token
+// literals share b's memory, so b must not be modified while the lexer
+// or its tokens are in use.
+func NewLexerFromBytes(b []byte, opts ...Option) *Lexer {
+	return NewLexer(unsafe.String(unsafe.SliceData(b), len(b)), opts...)
+}
+
+// NewLexerSafe creates a new lexer like NewLexer, but returns an error
+// instead of a lexer when the input exceeds the configured size limit
+func NewLexerSafe(input string, opts ...Option) (*Lexer, error) {
+	l := NewLexer(input, opts...)
+	if l.HasErrors() {
+		return nil, l.errors[0]
+	}
+	return l, nil
+}
+
+// NewLexerWithConfig creates a lexer like NewLexer with the tokens from
+// configFile. They apply to the returned lexer only; other lexers keep
+// the package tables. If the file cannot be loaded, it prints a warning
+// to stderr and continues with the default configuration.
+func NewLexerWithConfig(input, configFile string, opts ...Option) *Lexer {
 	config, err := LoadConfig(configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load config file '%s': %v\n", configFile, err)
 		fmt.Fprintf(os.Stderr, "Continuing with default configuration...\n")
 	} else {
-		config.MergeWithDefaults()
+		opts = append([]Option{func(l *Lexer) {
+			for _, warning := range l.applyConfig(config) {
+				fmt.Fprintf(os.Stderr, "Warning: config file '%s': %s\n", configFile, warning)
+			}
+		}}, opts...)
 	}
 
-	l := &Lexer{
-		input:  input,
-		line:   1,
-		column: 0,
-		errors: make([]*LexError, 0),
-	}
-	l.readChar()
-	return l
+	return NewLexer(input, opts...)
 }
 
 // GetErrors returns all lexical errors encountered during tokenization
@@ -127,8 +405,30 @@ func (l *Lexer) HasErrors() bool {
 	return len(l.errors) > 0
 }
 
-// TokenizeAll returns all tokens from the input along with any errors
-func (l *Lexer) TokenizeAll() ([]Token, []*LexError) {
+// SetSourceName sets the file name recorded in the tokens and errors
+// produced from now on, like WithSourceName
+func (l *Lexer) SetSourceName(name string) {
+	l.sourceName = name
+}
+
+// InputLength returns the length of the input in bytes
+func (l *Lexer) InputLength() int {
+	return len(l.input)
+}
+
+// LineCount returns the number of lines in the input
+func (l *Lexer) LineCount() int {
+	return l.lineCount
+}
+
+// IsAtEOF returns true once the lexer has consumed all of its input
+func (l *Lexer) IsAtEOF() bool {
+	return l.atEOF
+}
+
+// TokenizeAll returns all tokens from the input along with any errors.
+// The EOF token is not included, so empty input gives a nil slice.
+func (l *Lexer) TokenizeAll() ([]Token, LexErrorSlice) {
 	var tokens []Token
 
 	for {
@@ -142,16 +442,94 @@ func (l *Lexer) TokenizeAll() ([]Token, []*LexError) {
 	return tokens, l.errors
 }
 
-func (l *Lexer) addError(message string) {
+// Rewind restarts tokenization from the beginning of the same input, so
+// the input can be lexed again in a second pass. Errors and the types
+// reported by AllTokenTypes are cleared, except an oversized input error,
+// which still applies. Options and config are kept.
+func (l *Lexer) Rewind() {
+	errors := make([]*LexError, 0)
+	for _, err := range l.errors {
+		if err.Code == CodeInputTooLarge {
+			errors = append(errors, err)
+		}
+	}
+
+	l.errors = errors
+	l.tokenBuffer = nil
+	l.fstrings = nil
+	l.atLineStart = true
+	l.afterNewline = false
+	l.atEOF = false
+	l.lastType = ""
+	l.heredoc = nil
+	l.seenTypes = nil
+	l.seenTypeSet = nil
+	l.tokenCache = nil
+	l.cacheDone = false
+	l.position = 0
+	l.readPosition = 0
+	l.line = 1
+	l.column = 0
+	l.readChar()
+}
+
+// ScanOnly lexes the input and returns only the errors, without
+// collecting tokens. Useful for validation where tokens are not needed.
+func ScanOnly(input string) []*LexError {
+	l := NewLexer(input)
+	for l.NextToken().Type != EOF {
+	}
+	return l.errors
+}
+
+// ValidateSyntax returns nil if the input has no lexical errors, or a
+// single error combining every error found
+func ValidateSyntax(input string) error {
+	lexErrors := ScanOnly(input)
+	if len(lexErrors) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(lexErrors))
+	for i, e := range lexErrors {
+		errs[i] = e
+	}
+	return errors.Join(errs...)
+}
+
+func (l *Lexer) addError(code int, message string) {
+	l.addErrorAt(code, SeverityError, message, l.line, l.column)
+}
+
+// addErrorAt records an error at a position other than the cursor, such
+// as the start of an unterminated literal
+func (l *Lexer) addErrorAt(code int, severity ErrorSeverity, message string, line, column int) {
+	if l.errorMode == SilentMode {
+		return
+	}
 	l.errors = append(l.errors, &LexError{
-		Message: message,
-		Line:    l.line,
-		Column:  l.column,
+		Message:    message,
+		Line:       line,
+		Column:     column,
+		Code:       code,
+		Severity:   severity,
+		SourceFile: l.sourceName,
 	})
 }
 
 func (l *Lexer) readChar() {
+	// Skip heredoc bodies that were read with their opening tag
+	if l.heredoc != nil && l.position == l.heredoc.newline {
+		l.restoreCursor(l.heredoc.resume)
+		return
+	}
+
 	if l.readPosition >= len(l.input) {
+		// Reading past the end again leaves the position unchanged
+		if l.atEOF {
+			return
+		}
+		l.atEOF = true
 		l.ch = 0
 		l.position = l.readPosition
 	} else {
@@ -177,16 +555,93 @@ func (l *Lexer) peekChar() rune {
 	return r
 }
 
+// peekCharN returns the character n positions ahead of the current one
+// without consuming anything. peekCharN(1) is equivalent to peekChar.
+func (l *Lexer) peekCharN(n int) rune {
+	pos := l.readPosition
+	for i := 1; i < n; i++ {
+		if pos >= len(l.input) {
+			return 0
+		}
+		_, size := utf8.DecodeRuneInString(l.input[pos:])
+		pos += size
+	}
+	if pos >= len(l.input) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(l.input[pos:])
+	return r
+}
+
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
 		l.readChar()
 	}
 }
 
+// markSkipped starts a new line for the next token if the whitespace or
+// comment skipped since start contained a newline
+func (l *Lexer) markSkipped(start int) {
+	if strings.IndexByte(l.input[start:l.position], '\n') >= 0 {
+		l.atLineStart = true
+		l.afterNewline = true
+	}
+}
+
 func isLetter(ch rune) bool {
 	return unicode.IsLetter(ch) || ch == '_'
 }
 
+// isIdentStart reports whether ch can start an identifier in this lexer
+func (l *Lexer) isIdentStart(ch rune) bool {
+	return isLetter(ch) || (l.allowDollar && ch == '$')
+}
+
+// isIdentChar reports whether ch can continue an identifier in this lexer
+func (l *Lexer) isIdentChar(ch rune) bool {
+	return l.isIdentStart(ch) || isDigit(ch) || (l.unicodeIdent && isUnicodeIdentPart(ch))
+}
+
+// isUnicodeIdentPart reports whether ch is a letter number (Nl), a
+// nonspacing mark (Mn) or a spacing combining mark (Mc)
+func isUnicodeIdentPart(ch rune) bool {
+	return unicode.In(ch, unicode.Nl, unicode.Mn, unicode.Mc)
+}
+
+// operandEnds contains the token types after which ".5" is a member or
+// range access rather than a float, as in a.5, t[0].5 or 1..5
+var operandEnds = map[TokenType]struct{}{
+	IDENT:    {},
+	NUMBER:   {},
+	FLOAT:    {},
+	INT:      {},
+	RPAREN:   {},
+	RBRACKET: {},
+	RBRACE:   {},
+	DOT:      {},
+}
+
+// isDotFloat reports whether the cursor is on a float written without a
+// leading zero, such as .5
+func (l *Lexer) isDotFloat() bool {
+	if l.ch != '.' || !isDigit(l.peekChar()) {
+		return false
+	}
+	_, ends := operandEnds[l.lastType]
+	return !ends
+}
+
+// afterOperand reports whether the last token can end an operand, such
+// as an identifier, literal or closing bracket
+func (l *Lexer) afterOperand() bool {
+	switch l.lastType {
+	case IDENT, RPAREN, RBRACKET, RBRACE, TRUE, FALSE, NULL:
+		return true
+	}
+	_, literal := literalTypes[l.lastType]
+	return literal
+}
+
 func isDigit(ch rune) bool {
 	return unicode.IsDigit(ch)
 }
@@ -204,22 +659,30 @@ func isOctalDigit(ch rune) bool {
 }
 
 func (l *Lexer) readIdentifier() string {
+	if l.profiler != nil {
+		defer l.profile("readIdentifier", time.Now())
+	}
+
 	start := l.position
 
 	// First character must be letter or underscore
-	if !isLetter(l.ch) {
-		l.addError("identifier must start with a letter or underscore")
+	if !l.isIdentStart(l.ch) {
+		l.addError(CodeInvalidIdentifier, "identifier must start with a letter or underscore")
 		return ""
 	}
 
 	// Read the identifier - continue while we have letters or digits
-	for isLetter(l.ch) || isDigit(l.ch) {
+	for l.isIdentChar(l.ch) {
 		l.readChar()
 	}
 	return l.input[start:l.position]
 }
 
 func (l *Lexer) readNumber() string {
+	if l.profiler != nil {
+		defer l.profile("readNumber", time.Now())
+	}
+
 	start := l.position
 
 	// Check for hex, binary, or octal prefixes
@@ -234,27 +697,28 @@ func (l *Lexer) readNumber() string {
 		if next == 'o' || next == 'O' {
 			return l.readOctalNumber()
 		}
-		// Traditional octal (starts with 0)
-		if isOctalDigit(next) {
-			return l.readTraditionalOctal()
+		// Traditional octal (starts with 0), unless it is a float like 09.5
+		if isDigit(next) {
+			if literal, ok := l.readTraditionalOctal(); ok {
+				return literal
+			}
 		}
 	}
 
 	// Regular decimal number
-	for isDigit(l.ch) {
-		l.readChar()
-	}
+	l.readDigits(isDigit)
 
 	// Float with decimal point
+	isFloat := false
 	if l.ch == '.' && isDigit(l.peekChar()) {
+		isFloat = true
 		l.readChar() // consume '.'
-		for isDigit(l.ch) {
-			l.readChar()
-		}
+		l.readDigits(isDigit)
 	}
 
 	// Scientific notation
 	if l.ch == 'e' || l.ch == 'E' {
+		isFloat = true
 		l.readChar() // consume 'e' or 'E'
 
 		if l.ch == '+' || l.ch == '-' {
@@ -262,17 +726,28 @@ func (l *Lexer) readNumber() string {
 		}
 
 		if !isDigit(l.ch) {
-			l.addError("invalid scientific notation: exponent must contain digits")
-		} else {
-			for isDigit(l.ch) {
+			l.addError(CodeInvalidNumber, "invalid scientific notation: exponent must contain digits")
+			// Skip the rest of the malformed exponent so it is reported once
+			for l.ch == '+' || l.ch == '-' || isLetter(l.ch) || isDigit(l.ch) {
 				l.readChar()
 			}
+			return l.input[start:l.position]
 		}
+		l.readDigits(isDigit)
+	}
+
+	// A configured type suffix such as f64 or u8 may follow the digits
+	suffixes := l.intSuffixes
+	if isFloat {
+		suffixes = l.floatSuffixes
+	}
+	if l.readNumberSuffix(suffixes) {
+		return l.input[start:l.position]
 	}
 
 	// Check for invalid trailing characters
-	if isLetter(l.ch) && l.ch != 0 {
-		l.addError("invalid number: numbers cannot be followed by letters")
+	if isLetter(l.ch) {
+		l.addError(CodeInvalidNumber, "invalid number: numbers cannot be followed by letters")
 		// Skip the invalid characters to avoid cascading errors
 		for isLetter(l.ch) || isDigit(l.ch) {
 			l.readChar()
@@ -282,23 +757,95 @@ func (l *Lexer) readNumber() string {
 	return l.input[start:l.position]
 }
 
+// isSeparator reports whether ch is a digit separator in this lexer
+func (l *Lexer) isSeparator(ch rune) bool {
+	return l.separators && ch == '_'
+}
+
+// readDigits reads a run of digits accepted by valid. With separators
+// enabled, single underscores between digits are read too, and the first
+// misplaced one is reported.
+func (l *Lexer) readDigits(valid func(rune) bool) {
+	if !l.separators {
+		for valid(l.ch) {
+			l.readChar()
+		}
+		return
+	}
+
+	var problem string
+	line, column := 0, 0
+	report := func(message string) {
+		if problem == "" {
+			problem, line, column = message, l.line, l.column
+		}
+	}
+
+	first, afterSeparator := true, false
+	lastLine, lastColumn := 0, 0
+	for valid(l.ch) || l.ch == '_' {
+		if l.ch == '_' {
+			switch {
+			case first:
+				report("invalid number separator: digits cannot start with an underscore")
+			case afterSeparator:
+				report("invalid number separator: consecutive underscores")
+			}
+			afterSeparator = true
+			lastLine, lastColumn = l.line, l.column
+		} else {
+			afterSeparator = false
+		}
+		first = false
+		l.readChar()
+	}
+	if afterSeparator && problem == "" {
+		problem = "invalid number separator: number cannot end with an underscore"
+		line, column = lastLine, lastColumn
+	}
+	if problem != "" {
+		l.addErrorAt(CodeInvalidNumber, SeverityError, problem, line, column)
+	}
+}
+
+// readNumberSuffix consumes the first of suffixes found at the cursor,
+// provided it is not followed by further identifier characters
+func (l *Lexer) readNumberSuffix(suffixes []string) bool {
+	rest := l.input[l.position:]
+	for _, suffix := range suffixes {
+		if !strings.HasPrefix(rest, suffix) {
+			continue
+		}
+		if next, _ := utf8.DecodeRuneInString(rest[len(suffix):]); l.isIdentChar(next) {
+			continue
+		}
+		for range suffix {
+			l.readChar()
+		}
+		return true
+	}
+	return false
+}
+
 func (l *Lexer) readHexNumber() string {
 	start := l.position
 	l.readChar() // skip '0'
 	l.readChar() // skip 'x' or 'X'
 
-	if !isHexDigit(l.ch) {
-		l.addError("invalid hexadecimal number: must contain at least one hex digit after 0x")
+	if !isHexDigit(l.ch) && !l.isSeparator(l.ch) {
+		l.addError(CodeInvalidNumber, "invalid hexadecimal number: must contain at least one hex digit after 0x")
 		return l.input[start:l.position]
 	}
 
-	for isHexDigit(l.ch) {
-		l.readChar()
+	l.readDigits(isHexDigit)
+
+	if l.readNumberSuffix(l.intSuffixes) {
+		return l.input[start:l.position]
 	}
 
 	// Check for invalid trailing characters
-	if isLetter(l.ch) && l.ch != 0 {
-		l.addError("invalid hexadecimal number: contains non-hex characters")
+	if isLetter(l.ch) {
+		l.addError(CodeInvalidNumber, "invalid hexadecimal number: contains non-hex characters")
 		for isLetter(l.ch) || isDigit(l.ch) {
 			l.readChar()
 		}
@@ -312,18 +859,20 @@ func (l *Lexer) readBinaryNumber() string {
 	l.readChar() // skip '0'
 	l.readChar() // skip 'b' or 'B'
 
-	if !isBinaryDigit(l.ch) {
-		l.addError("invalid binary number: must contain at least one binary digit after 0b")
+	if !isBinaryDigit(l.ch) && !l.isSeparator(l.ch) {
+		l.addError(CodeInvalidNumber, "invalid binary number: must contain at least one binary digit after 0b")
 		return l.input[start:l.position]
 	}
 
-	for isBinaryDigit(l.ch) {
-		l.readChar()
+	l.readDigits(isBinaryDigit)
+
+	if l.readNumberSuffix(l.intSuffixes) {
+		return l.input[start:l.position]
 	}
 
 	// Check for invalid trailing characters
 	if (isDigit(l.ch) && !isBinaryDigit(l.ch)) || isLetter(l.ch) {
-		l.addError("invalid binary number: contains non-binary characters")
+		l.addError(CodeInvalidNumber, "invalid binary number: contains non-binary characters")
 		for isLetter(l.ch) || isDigit(l.ch) {
 			l.readChar()
 		}
@@ -337,48 +886,60 @@ func (l *Lexer) readOctalNumber() string {
 	l.readChar() // skip '0'
 	l.readChar() // skip 'o' or 'O'
 
-	if !isOctalDigit(l.ch) {
-		l.addError("invalid octal number: must contain at least one octal digit after 0o")
+	if !isDigit(l.ch) && !l.isSeparator(l.ch) {
+		l.addError(CodeInvalidNumber, "invalid octal number: must contain at least one octal digit after 0o")
 		return l.input[start:l.position]
 	}
-
-	for isOctalDigit(l.ch) {
-		l.readChar()
-	}
-
-	// Check for invalid trailing characters
-	if (isDigit(l.ch) && !isOctalDigit(l.ch)) || isLetter(l.ch) {
-		l.addError("invalid octal number: contains non-octal characters")
-		for isLetter(l.ch) || isDigit(l.ch) {
-			l.readChar()
-		}
-	}
-
-	return l.input[start:l.position]
+	return l.readOctalDigits(start)
 }
 
-func (l *Lexer) readTraditionalOctal() string {
+// readTraditionalOctal reads a number with a leading zero such as 0755. It
+// reports false without consuming anything when the digits are followed
+// by a fraction or exponent, since 09.5 and 0755e1 are decimal floats.
+func (l *Lexer) readTraditionalOctal() (string, bool) {
 	start := l.position
+	saved := l.saveCursor()
 
-	for isOctalDigit(l.ch) {
+	for isDigit(l.ch) || l.isSeparator(l.ch) {
 		l.readChar()
 	}
+	isFloat := (l.ch == '.' && isDigit(l.peekChar())) || l.ch == 'e' || l.ch == 'E'
+	l.restoreCursor(saved)
+	if isFloat {
+		return "", false
+	}
+	return l.readOctalDigits(start), true
+}
 
-	// Check for invalid trailing characters
-	if (isDigit(l.ch) && !isOctalDigit(l.ch)) || isLetter(l.ch) {
-		l.addError("invalid octal number: contains non-octal characters")
-		for isLetter(l.ch) || isDigit(l.ch) {
-			l.readChar()
-		}
+// readOctalDigits reads the digits of an octal literal that began at
+// start. The first 8 or 9 is reported where it appears, and the rest of
+// the malformed literal is skipped to avoid cascading errors.
+func (l *Lexer) readOctalDigits(start int) string {
+	line, column, digits := l.line, l.column, l.position
+	l.readDigits(func(ch rune) bool { return ch >= '0' && ch <= '9' })
+	bad := strings.IndexAny(l.input[digits:l.position], "89")
+	if bad < 0 && l.readNumberSuffix(l.intSuffixes) {
+		return l.input[start:l.position]
 	}
 
+	switch {
+	case bad >= 0:
+		l.addErrorAt(CodeInvalidNumber, SeverityError, "octal literal cannot contain digits 8 or 9", line, column+bad)
+	case isDigit(l.ch) || isLetter(l.ch):
+		l.addError(CodeInvalidNumber, "invalid octal number: contains non-octal characters")
+	default:
+		return l.input[start:l.position]
+	}
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
 	return l.input[start:l.position]
 }
 
 func (l *Lexer) readEscapeSequence() rune {
 	l.readChar() // consume backslash
-	if l.ch == 0 {
-		l.addError("unterminated escape sequence")
+	if l.atEOF {
+		l.addError(CodeInvalidEscape, "unterminated escape sequence")
 		return -1
 	}
 
@@ -406,27 +967,30 @@ func (l *Lexer) readEscapeSequence() rune {
 	case '0', '1', '2', '3', '4', '5', '6', '7':
 		// Octal escape sequence \NNN (1-3 octal digits)
 		val := rune(l.ch - '0')
-		for i := 0; i < 2; i++ {
-			next := l.peekChar()
-			if next >= '0' && next <= '7' {
-				l.readChar()
-				val = val*8 + rune(l.ch-'0')
-			} else {
-				break
-			}
+		digits := 1
+		for ; digits < 3 && isOctalDigit(l.peekChar()); digits++ {
+			l.readChar()
+			val = val*8 + rune(l.ch-'0')
+		}
+		if next := l.peekChar(); digits < 3 && (next == '8' || next == '9') {
+			l.addError(CodeInvalidEscape, fmt.Sprintf("invalid octal escape sequence: '%c' is not an octal digit", next))
+		}
+		if val > 0377 {
+			l.addError(CodeInvalidEscape, "octal escape value > 255")
+			return -1
 		}
 		return val
 	case 'x':
 		// Hex escape sequence \xNN
 		l.readChar()
 		if !isHexDigit(l.ch) {
-			l.addError("invalid hex escape sequence: expected hex digit after \\x")
+			l.addError(CodeInvalidEscape, "invalid hex escape sequence: expected hex digit after \\x")
 			return -1
 		}
 		first := l.ch
 		l.readChar()
 		if !isHexDigit(l.ch) {
-			l.addError("invalid hex escape sequence: expected two hex digits after \\x")
+			l.addError(CodeInvalidEscape, "invalid hex escape sequence: expected two hex digits after \\x")
 			return -1
 		}
 		second := l.ch
@@ -447,24 +1011,110 @@ func (l *Lexer) readEscapeSequence() rune {
 			val += second - 'A' + 10
 		}
 		return val
+	case 'u':
+		// Unicode escape \uNNNN or \u{N...}
+		if l.peekChar() == '{' {
+			return l.readBracedUnicodeEscape()
+		}
+		return l.validateCodePoint(l.readHexEscape(4, "\\u"))
+	case 'U':
+		// Unicode escape \UNNNNNNNN
+		return l.validateCodePoint(l.readHexEscape(8, "\\U"))
 	default:
-		l.addError(fmt.Sprintf("unknown escape sequence '\\%c'", l.ch))
+		l.addError(CodeInvalidEscape, fmt.Sprintf("unknown escape sequence '\\%c'", l.ch))
 		return l.ch
 	}
 }
 
+// hexDigitValue returns the value of an ASCII hex digit
+func hexDigitValue(ch rune) (rune, bool) {
+	switch {
+	case ch >= '0' && ch <= '9':
+		return ch - '0', true
+	case ch >= 'a' && ch <= 'f':
+		return ch - 'a' + 10, true
+	case ch >= 'A' && ch <= 'F':
+		return ch - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// readHexEscape reads exactly n hex digits following the current character
+func (l *Lexer) readHexEscape(n int, prefix string) rune {
+	var val rune
+	for i := 0; i < n; i++ {
+		digit, ok := hexDigitValue(l.peekChar())
+		if !ok {
+			l.addError(CodeInvalidEscape, fmt.Sprintf("invalid Unicode escape sequence: expected %d hex digits after %s", n, prefix))
+			return -1
+		}
+		l.readChar()
+		val = val*16 + digit
+	}
+	return val
+}
+
+// readBracedUnicodeEscape reads the \u{N...} form holding 1 to 6 hex digits
+func (l *Lexer) readBracedUnicodeEscape() rune {
+	l.readChar() // consume '{'
+
+	var val rune
+	digits := 0
+	for {
+		next := l.peekChar()
+		if next == '}' {
+			l.readChar()
+			break
+		}
+		digit, ok := hexDigitValue(next)
+		if !ok {
+			l.addError(CodeInvalidEscape, "invalid Unicode escape sequence: \\u{...} must contain only hex digits and be closed with '}'")
+			return -1
+		}
+		l.readChar()
+		val = val*16 + digit
+		digits++
+		if digits > 6 {
+			l.addError(CodeInvalidEscape, "invalid Unicode escape sequence: \\u{...} accepts at most 6 hex digits")
+			return -1
+		}
+	}
+
+	if digits == 0 {
+		l.addError(CodeInvalidEscape, "invalid Unicode escape sequence: \\u{} must contain at least one hex digit")
+		return -1
+	}
+	return l.validateCodePoint(val)
+}
+
+// validateCodePoint rejects surrogates and values beyond the Unicode range
+func (l *Lexer) validateCodePoint(r rune) rune {
+	if r == -1 {
+		return -1
+	}
+	if !utf8.ValidRune(r) {
+		l.addError(CodeInvalidEscape, fmt.Sprintf("invalid Unicode escape sequence: U+%04X is not a valid Unicode scalar value", r))
+		return -1
+	}
+	return r
+}
+
 func (l *Lexer) readCharLiteral() string {
+	if l.profiler != nil {
+		defer l.profile("readCharLiteral", time.Now())
+	}
+
 	var result strings.Builder
 
 	l.readChar() // consume opening '
 
-	if l.ch == 0 {
-		l.addError("unterminated character literal")
+	if l.atEOF {
+		l.addError(CodeUnterminatedChar, "unterminated character literal")
 		return ""
 	}
 
 	if l.ch == '\n' {
-		l.addError("character literal cannot contain newline")
+		l.addError(CodeUnterminatedChar, "character literal cannot contain newline")
 		return ""
 	}
 
@@ -479,7 +1129,7 @@ func (l *Lexer) readCharLiteral() string {
 
 	l.readChar()
 	if l.ch != '\'' {
-		l.addError("character literal must be closed with single quote")
+		l.addError(CodeUnterminatedChar, "character literal must be closed with single quote")
 	} else {
 		l.readChar() // consume closing '
 	}
@@ -488,16 +1138,21 @@ func (l *Lexer) readCharLiteral() string {
 }
 
 func (l *Lexer) readString() (string, bool) {
+	if l.profiler != nil {
+		defer l.profile("readString", time.Now())
+	}
+
 	var result strings.Builder
 	l.tokenBuffer = nil
 	interpolated := false
 	startLine := l.line
 	startColumn := l.column
+	startOffset := l.position
 
 	for {
 		l.readChar()
-		if l.ch == 0 {
-			l.addError("unterminated string literal")
+		if l.atEOF {
+			l.addErrorAt(CodeUnterminatedString, SeverityError, "unterminated string literal", startLine, startColumn)
 			break
 		}
 		if l.ch == '"' {
@@ -521,10 +1176,12 @@ func (l *Lexer) readString() (string, bool) {
 			interpolated = true
 
 			l.tokenBuffer = append(l.tokenBuffer, Token{
-				Type:    STRING_PART,
-				Literal: result.String(),
-				Line:    startLine,
-				Column:  startColumn,
+				Type:      STRING_PART,
+				Literal:   result.String(),
+				Line:      startLine,
+				Column:    startColumn,
+				Offset:    startOffset,
+				EndOffset: l.position,
 			})
 			result.Reset()
 
@@ -540,6 +1197,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: "{",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -552,13 +1210,14 @@ func (l *Lexer) readString() (string, bool) {
 							Literal: "}",
 							Line:    l.line,
 							Column:  l.column,
+							Offset:  l.position,
 						})
 						l.readChar()
 					}
 					continue
 				}
-				if l.ch == 0 {
-					l.addError("unterminated interpolated expression")
+				if l.atEOF {
+					l.addError(CodeUnterminatedString, "unterminated interpolated expression")
 					break
 				}
 				if isLetter(l.ch) || l.ch == '_' {
@@ -568,10 +1227,11 @@ func (l *Lexer) readString() (string, bool) {
 					}
 					ident := l.input[identStart:l.position]
 					l.tokenBuffer = append(l.tokenBuffer, Token{
-						Type:    LookupIdent(ident),
+						Type:    l.lookupIdent(ident),
 						Literal: ident,
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  identStart,
 					})
 					continue
 				}
@@ -586,6 +1246,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: num,
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  numStart,
 					})
 					continue
 				}
@@ -597,10 +1258,11 @@ func (l *Lexer) readString() (string, bool) {
 					}
 					num := l.input[numStart:l.position]
 					l.tokenBuffer = append(l.tokenBuffer, Token{
-						Type:    NUMBER,
+						Type:    FLOAT,
 						Literal: num,
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  numStart,
 					})
 					continue
 				}
@@ -610,6 +1272,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: "(",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -620,6 +1283,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: ")",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -630,6 +1294,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: "[",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -640,6 +1305,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: "]",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -650,6 +1316,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: ",",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -660,6 +1327,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: ":",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -670,6 +1338,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: "+",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -680,6 +1349,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: "-",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -690,6 +1360,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: ".",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -700,6 +1371,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: "*",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -710,6 +1382,7 @@ func (l *Lexer) readString() (string, bool) {
 						Literal: "/",
 						Line:    l.line,
 						Column:  l.column,
+						Offset:  l.position,
 					})
 					l.readChar()
 					continue
@@ -718,18 +1391,26 @@ func (l *Lexer) readString() (string, bool) {
 					l.readChar()
 					continue
 				}
-				l.addError(fmt.Sprintf("unexpected character '%c' in interpolated expression", l.ch))
+				l.addError(CodeUnexpectedChar, fmt.Sprintf("unexpected character '%c' in interpolated expression", l.ch))
 				l.readChar()
 			}
 
+			interpEnd := l.position
+			if l.ch == '}' {
+				interpEnd++
+			}
 			l.tokenBuffer = append(l.tokenBuffer, Token{
-				Type:    INTERP_END,
-				Literal: "",
-				Line:    l.line,
-				Column:  l.column,
+				Type:      INTERP_END,
+				Literal:   "",
+				Line:      l.line,
+				Column:    l.column,
+				Offset:    l.position,
+				EndOffset: interpEnd,
 			})
+			// The next part starts after the closing brace
 			startLine = l.line
-			startColumn = l.column
+			startColumn = l.column + 1
+			startOffset = l.position + 1
 			continue
 		}
 		result.WriteRune(l.ch)
@@ -738,47 +1419,410 @@ func (l *Lexer) readString() (string, bool) {
 	if interpolated {
 		if result.Len() > 0 {
 			l.tokenBuffer = append(l.tokenBuffer, Token{
-				Type:    STRING_PART,
-				Literal: result.String(),
-				Line:    startLine,
-				Column:  startColumn,
+				Type:      STRING_PART,
+				Literal:   result.String(),
+				Line:      startLine,
+				Column:    startColumn,
+				Offset:    startOffset,
+				EndOffset: l.position,
 			})
 		}
+		// Tokens inside ${...} are spelled exactly as in the input
+		for i := range l.tokenBuffer {
+			if l.tokenBuffer[i].EndOffset == 0 {
+				l.tokenBuffer[i].EndOffset = l.tokenBuffer[i].Offset + len(l.tokenBuffer[i].Literal)
+			}
+		}
 		return "", true
 	}
 
 	return result.String(), false
 }
 
-func (l *Lexer) readBacktickString() string {
+// readTripleString reads a """...""" string. Escape sequences are processed
+// and newlines are kept. A run of more than three quotes closes the string
+// with its last three, so """a"""" is the string a".
+func (l *Lexer) readTripleString() string {
+	if l.profiler != nil {
+		defer l.profile("readTripleString", time.Now())
+	}
+
 	var result strings.Builder
 
+	l.readChar() // consume opening quotes
+	l.readChar()
+	l.readChar()
+
 	for {
-		l.readChar()
-		if l.ch == 0 {
-			l.addError("unterminated backtick string literal")
-			break
-		}
-		if l.ch == '`' {
+		if l.atEOF {
+			l.addError(CodeUnterminatedString, "unterminated triple-quoted string literal")
 			break
 		}
-		result.WriteRune(l.ch)
-	}
+		if l.ch == '"' {
+			run := 0
+			for l.ch == '"' {
+				run++
+				l.readChar()
+			}
+			if run >= 3 {
+				result.WriteString(strings.Repeat(`"`, run-3))
+				break
+			}
+			result.WriteString(strings.Repeat(`"`, run))
+			continue
+		}
+		if l.ch == '\\' {
+			char := l.readEscapeSequence()
+			if char != -1 {
+				result.WriteRune(char)
+			}
+			l.readChar()
+			continue
+		}
+		result.WriteRune(l.ch)
+		l.readChar()
+	}
 
 	return result.String()
 }
 
+func (l *Lexer) readBacktickString() string {
+	if l.profiler != nil {
+		defer l.profile("readBacktickString", time.Now())
+	}
+
+	var result strings.Builder
+	line, column := l.line, l.column
+
+	for {
+		l.readChar()
+		if l.atEOF {
+			// Report where the string starts, since the end of input may
+			// be many lines later
+			l.addErrorAt(CodeUnterminatedString, SeverityError, "unterminated backtick string literal", line, column)
+			break
+		}
+		if l.ch == '`' {
+			break
+		}
+		result.WriteRune(l.ch)
+	}
+
+	return result.String()
+}
+
+// readFormatStringPart returns the next token of an f-string outside its
+// {...} expressions: the text up to the next expression as FSTRING_MIDDLE,
+// the brace opening an expression, or the closing quote as FSTRING_END.
+// Doubled braces stand for literal braces and escapes are processed.
+func (l *Lexer) readFormatStringPart() Token {
+	fs := &l.fstrings[len(l.fstrings)-1]
+	line, column, offset := l.line, l.column, l.position
+
+	switch {
+	case l.ch == fs.quote:
+		l.fstrings = l.fstrings[:len(l.fstrings)-1]
+		l.readChar()
+		return Token{Type: FSTRING_END, Literal: string(fs.quote), Line: line, Column: column, Offset: offset}
+	case l.ch == '{' && l.peekChar() != '{':
+		fs.depth = 1
+		l.readChar()
+		return Token{Type: LBRACE, Literal: "{", Line: line, Column: column, Offset: offset}
+	}
+
+	var result strings.Builder
+	for l.ch != fs.quote && !(l.ch == '{' && l.peekChar() != '{') {
+		switch {
+		case l.atEOF:
+			l.closeFormatStrings()
+			if result.Len() == 0 {
+				return Token{Type: EOF, Line: line, Column: column, Offset: offset}
+			}
+			return Token{Type: FSTRING_MIDDLE, Literal: result.String(), Line: line, Column: column, Offset: offset}
+		case (l.ch == '{' || l.ch == '}') && l.peekChar() == l.ch:
+			result.WriteRune(l.ch)
+			l.readChar()
+		case l.ch == '}':
+			l.addError(CodeUnexpectedChar, "single '}' in format string text; write '}}' for a literal brace")
+			result.WriteRune(l.ch)
+		case l.ch == '\\':
+			if char := l.readEscapeSequence(); char != -1 {
+				result.WriteRune(char)
+			}
+		default:
+			result.WriteRune(l.ch)
+		}
+		l.readChar()
+	}
+	return Token{Type: FSTRING_MIDDLE, Literal: result.String(), Line: line, Column: column, Offset: offset}
+}
+
+// trackFormatBraces follows the braces of an f-string expression so that
+// the brace closing it returns the lexer to reading text
+func (l *Lexer) trackFormatBraces(tok Token) {
+	fs := &l.fstrings[len(l.fstrings)-1]
+	switch tok.Type {
+	case LBRACE:
+		fs.depth++
+	case RBRACE:
+		fs.depth--
+	case EOF:
+		l.closeFormatStrings()
+	}
+}
+
+// closeFormatStrings abandons the open f-strings at the end of input,
+// reporting the outermost one
+func (l *Lexer) closeFormatStrings() {
+	fs := l.fstrings[0]
+	l.addErrorAt(CodeUnterminatedString, SeverityError, "unterminated format string literal", fs.line, fs.column)
+	l.fstrings = nil
+}
+
+// readTripleBacktick reads a ```...``` block. Nothing inside is escaped,
+// and newlines are kept.
+func (l *Lexer) readTripleBacktick() string {
+	if l.profiler != nil {
+		defer l.profile("readTripleBacktick", time.Now())
+	}
+
+	line, column := l.line, l.column
+	l.readChar() // consume opening backticks
+	l.readChar()
+	l.readChar()
+
+	start := l.position
+	for {
+		if l.atEOF {
+			l.addErrorAt(CodeUnterminatedString, SeverityError, "unterminated triple backtick string literal", line, column)
+			return l.input[start:l.position]
+		}
+		if strings.HasPrefix(l.input[l.position:], "```") {
+			str := l.input[start:l.position]
+			l.readChar()
+			l.readChar()
+			l.readChar()
+			return str
+		}
+		l.readChar()
+	}
+}
+
+// readRawString reads a string closed by delim without processing escapes.
+// A backslash is kept as written, and a delimiter right after one does not
+// close the string, so r"a\"b" is the string a\"b.
+func (l *Lexer) readRawString(delim rune) string {
+	if l.profiler != nil {
+		defer l.profile("readRawString", time.Now())
+	}
+
+	var result strings.Builder
+	line, column := l.line, l.column-1
+
+	for {
+		l.readChar()
+		if l.atEOF {
+			l.addErrorAt(CodeUnterminatedString, SeverityError, "unterminated raw string literal", line, column)
+			return result.String()
+		}
+		if l.ch == delim {
+			l.readChar()
+			return result.String()
+		}
+		if l.ch == '\\' && l.peekChar() == delim {
+			result.WriteRune(l.ch)
+			l.readChar()
+		}
+		result.WriteRune(l.ch)
+	}
+}
+
+// tryCustomString reads a string opened by one of the configured
+// delimiters at the cursor, trying them in config order
+func (l *Lexer) tryCustomString(line, column, offset int) (Token, bool) {
+	rest := l.input[l.position:]
+	for _, pair := range l.stringDelimiters {
+		if !strings.HasPrefix(rest, pair.Open) {
+			continue
+		}
+
+		for range pair.Open {
+			l.readChar()
+		}
+		str, closed := l.readCustomString(pair.Close, pair.AllowEscapes)
+		if !closed {
+			l.addErrorAt(CodeUnterminatedString, SeverityError, fmt.Sprintf("unterminated string literal: expected closing %q", pair.Close), line, column)
+		}
+		return Token{Type: TokenType(pair.TokenType), Literal: str, Line: line, Column: column, Offset: offset}, true
+	}
+	return Token{}, false
+}
+
+// readCustomString reads a string body up to and including close. With
+// allowEscapes, escape sequences are processed and a backslash before
+// close keeps the delimiter in the string. It reports false if the input
+// ends first.
+func (l *Lexer) readCustomString(close string, allowEscapes bool) (string, bool) {
+	var result strings.Builder
+
+	for {
+		if l.atEOF {
+			return result.String(), false
+		}
+		if strings.HasPrefix(l.input[l.position:], close) {
+			for range close {
+				l.readChar()
+			}
+			return result.String(), true
+		}
+		if allowEscapes && l.ch == '\\' {
+			if strings.HasPrefix(l.input[l.readPosition:], close) {
+				result.WriteString(close)
+				l.readChar()
+				for range close {
+					l.readChar()
+				}
+				continue
+			}
+			if char := l.readEscapeSequence(); char != -1 {
+				result.WriteRune(char)
+			}
+			l.readChar()
+			continue
+		}
+		result.WriteRune(l.ch)
+		l.readChar()
+	}
+}
+
+// readByteString reads a byte string literal from its opening quote. The
+// value holds the bytes the literal spells: \xNN and octal escapes are
+// single bytes rather than UTF-8 encoded runes, and ${ is plain text. The
+// first non-ASCII source character is reported at line and column, the
+// start of the literal, as a warning unless the lexer is in strict mode.
+func (l *Lexer) readByteString(line, column int) string {
+	var result strings.Builder
+	startLine := l.line
+	startColumn := l.column
+	reported := false
+
+	for {
+		l.readChar()
+		if l.atEOF {
+			l.addErrorAt(CodeUnterminatedString, SeverityError, "unterminated string literal", startLine, startColumn)
+			return result.String()
+		}
+		switch l.ch {
+		case '"':
+			l.readChar()
+			return result.String()
+		case '\\':
+			next := l.peekChar()
+			if next == '$' {
+				result.WriteByte('$')
+				l.readChar()
+				continue
+			}
+			char := l.readEscapeSequence()
+			if char == -1 {
+				continue
+			}
+			if next == 'x' || isOctalDigit(next) {
+				result.WriteByte(byte(char))
+			} else {
+				result.WriteRune(char)
+			}
+		default:
+			if l.ch > unicode.MaxASCII && !reported {
+				severity := SeverityWarning
+				if l.errorMode == StrictMode {
+					severity = SeverityError
+				}
+				l.addErrorAt(CodeNonASCIIByte, severity, fmt.Sprintf("non-ASCII character '%c' (Unicode: U+%04X) in byte string literal", l.ch, l.ch), line, column)
+				reported = true
+			}
+			result.WriteRune(l.ch)
+		}
+	}
+}
+
+// readHeredoc reads the lines following the current one until a line
+// holding only tag (plus optional trailing whitespace). The body excludes
+// the final newline before the terminator. The cursor is left after the
+// tag so the rest of the opening line is lexed as usual, and the body is
+// skipped at the end of that line. A second heredoc on the same line
+// reads its body after the first one's closing tag.
+func (l *Lexer) readHeredoc(tag string) string {
+	if l.profiler != nil {
+		defer l.profile("readHeredoc", time.Now())
+	}
+
+	afterTag := l.saveCursor()
+	newline := -1
+	if l.heredoc != nil {
+		newline = l.heredoc.newline
+		l.restoreCursor(l.heredoc.resume)
+	} else {
+		// The body starts on the line after the opening tag
+		for l.ch != '\n' && !l.atEOF {
+			l.readChar()
+		}
+		if !l.atEOF {
+			newline = l.position
+		}
+	}
+	defer func() {
+		resume := l.saveCursor()
+		l.restoreCursor(afterTag)
+		if newline >= 0 {
+			l.heredoc = &heredocJump{newline: newline, resume: resume}
+		}
+	}()
+
+	var lines []string
+	for {
+		if l.atEOF {
+			l.addError(CodeUnterminatedString, fmt.Sprintf("unterminated heredoc: missing closing tag '%s'", tag))
+			break
+		}
+		l.readChar() // consume '\n'
+
+		start := l.position
+		for l.ch != '\n' && !l.atEOF {
+			l.readChar()
+		}
+		text := l.input[start:l.position]
+
+		if strings.TrimRight(text, " \t\r") == tag {
+			break
+		}
+		lines = append(lines, text)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func (l *Lexer) skipLineComment() {
-	for l.ch != '\n' && l.ch != 0 {
+	for l.ch != '\n' && !l.atEOF {
 		l.readChar()
 	}
 }
 
+// commentToken builds a token for the comment that was just skipped,
+// starting at offset. Comments beginning with docPrefix are doc comments.
+func (l *Lexer) commentToken(tokType TokenType, docPrefix string, line, column, offset int) Token {
+	literal := l.input[offset:l.position]
+	if strings.HasPrefix(literal, docPrefix) && !strings.HasPrefix(literal, docPrefix+"/") {
+		tokType = DOC_COMMENT
+	}
+	return Token{Type: tokType, Literal: literal, Line: line, Column: column, Offset: offset}
+}
+
 func (l *Lexer) skipBlockComment() {
 	l.readChar() // consume initial '*'
 	for {
-		if l.ch == 0 {
-			l.addError("unterminated block comment")
+		if l.atEOF {
+			l.addError(CodeUnterminatedComment, "unterminated block comment")
 			return
 		}
 		if l.ch == '*' && l.peekChar() == '/' {
@@ -790,139 +1834,389 @@ func (l *Lexer) skipBlockComment() {
 	}
 }
 
-// tryOperator attempts to match an operator and returns the token if found
-func (l *Lexer) tryOperator(line, column int) (Token, bool) {
-	for _, op := range operators {
-		if l.ch == rune(op.Single[0]) {
-			if l.ch == '-' && l.peekChar() == '>' {
-				l.readChar()
-				return Token{
-					Type:    ARROW,
-					Literal: "->",
-					Line:    line,
-					Column:  column,
-				}, true
-			}
-			if l.ch == '|' && l.peekChar() == '>' {
-				l.readChar()
-				return Token{
-					Type:    PIPE,
-					Literal: "|>",
-					Line:    line,
-					Column:  column,
-				}, true
-			}
-			if l.ch == '+' && l.peekChar() == '+' {
-				l.readChar()
-				return Token{
-					Type:    INCREMENT,
-					Literal: "++",
-					Line:    line,
-					Column:  column,
-				}, true
-			}
-			if l.ch == '-' && l.peekChar() == '-' {
+// tryMultiWordOperator checks whether first begins a configured multi-word
+// operator and, if the following words match, consumes them. The longest
+// matching operator wins; on no match the lexer position is unchanged.
+func (l *Lexer) tryMultiWordOperator(first string, line, column, offset int) (Token, bool) {
+	var best *MultiWordOperator
+	var bestEnd cursor
+
+	start := l.saveCursor()
+	for i := range l.multiWordOps {
+		op := &l.multiWordOps[i]
+		if len(op.Words) < 2 || op.Words[0] != first {
+			continue
+		}
+		if best != nil && len(op.Words) <= len(best.Words) {
+			continue
+		}
+
+		matched := true
+		for _, word := range op.Words[1:] {
+			for l.ch == ' ' || l.ch == '\t' {
 				l.readChar()
-				return Token{
-					Type:    DECREMENT,
-					Literal: "--",
-					Line:    line,
-					Column:  column,
-				}, true
 			}
-			// Handle special cases for & and | which require compound form
-			if (l.ch == '&' || l.ch == '|') && op.Compound != "" {
-				if l.peekChar() == rune(op.Compound[1]) {
-					ch := l.ch
-					l.readChar() // consume first character
-					result := Token{
-						Type:    op.CompoundType,
-						Literal: string(ch) + string(l.ch),
-						Line:    line,
-						Column:  column,
-					}
-					return result, true
-				} else {
-					// Single & or | is an error
-					suggestion := op.Compound
-					l.addError(fmt.Sprintf("unexpected character '%c' - did you mean '%s'?", l.ch, suggestion))
-					return Token{Type: ILLEGAL, Literal: string(l.ch), Line: line, Column: column}, true
-				}
+			if !l.isIdentStart(l.ch) || l.readIdentifier() != word {
+				matched = false
+				break
 			}
+		}
+		if matched {
+			best = op
+			bestEnd = l.saveCursor()
+		}
+		l.restoreCursor(start)
+	}
 
-			// Check for compound operator
-			if op.Compound != "" && len(op.Compound) > 1 && l.peekChar() == rune(op.Compound[1]) {
-				ch := l.ch
-				l.readChar() // consume first character
-				return Token{
-					Type:    op.CompoundType,
-					Literal: string(ch) + string(l.ch),
-					Line:    line,
-					Column:  column,
-				}, true
-			}
+	if best == nil {
+		return Token{}, false
+	}
+	l.restoreCursor(bestEnd)
+	return Token{
+		Type:    best.Type,
+		Literal: strings.Join(best.Words, " "),
+		Line:    line,
+		Column:  column,
+		Offset:  offset,
+	}, true
+}
 
-			// Return single operator (if it has a valid single form)
-			if op.SingleType != "" {
-				return Token{
-					Type:    op.SingleType,
-					Literal: op.Single,
-					Line:    line,
-					Column:  column,
-				}, true
-			}
+// tryMultiCharToken matches the longest configured multi-character
+// punctuation at the cursor, leaving l.ch on its last character
+func (l *Lexer) tryMultiCharToken(line, column, offset int) (Token, bool) {
+	rest := l.input[l.position:]
+	best := ""
+	for literal := range l.multiCharTokens {
+		if len(literal) > len(best) && strings.HasPrefix(rest, literal) {
+			best = literal
+		}
+	}
+	if best == "" {
+		return Token{}, false
+	}
+
+	for i := 1; i < utf8.RuneCountInString(best); i++ {
+		l.readChar()
+	}
+	return Token{Type: l.multiCharTokens[best], Literal: best, Line: line, Column: column, Offset: offset}, true
+}
+
+// tryOperator attempts to match an operator and returns the token if found
+func (l *Lexer) tryOperator(line, column, offset int) (Token, bool) {
+	if l.profiler != nil {
+		defer l.profile("tryOperator", time.Now())
+	}
+
+	// The forms are sorted so the first match has the highest priority
+	// and, among those, the longest literal
+	rest := l.input[l.position:]
+	for _, form := range l.operatorOrder {
+		if !strings.HasPrefix(rest, form.literal) {
+			continue
+		}
+		// Leave l.ch on the last character of the operator
+		for i := 1; i < utf8.RuneCountInString(form.literal); i++ {
+			l.readChar()
+		}
+		return Token{
+			Type:    form.tokType,
+			Literal: form.literal,
+			Line:    line,
+			Column:  column,
+			Offset:  offset,
+		}, true
+	}
+
+	// Single & or | is an error
+	for _, op := range l.operators {
+		if op.SingleType == "" && op.Single != "" && l.ch == rune(op.Single[0]) && op.Compound != "" {
+			l.addError(CodeUnexpectedChar, fmt.Sprintf("unexpected character '%c' - did you mean '%s'?", l.ch, op.Compound))
+			return Token{Type: ILLEGAL, Literal: string(l.ch), Line: line, Column: column, Offset: offset}, true
 		}
 	}
 	return Token{}, false
 }
 
+// NextToken returns the next token in the input. Errors do not stop the
+// lexer: the offending text becomes an ILLEGAL token and the error is
+// recorded. At the end of input it returns EOF on every call.
 func (l *Lexer) NextToken() Token {
-	var tok Token
+	if l.profiler != nil {
+		defer l.profile("NextToken", time.Now())
+	}
+
+	for {
+		if l.errorMode == StrictMode && len(l.errors) > 0 {
+			l.cacheDone = l.cacheTokens
+			return Token{Type: EOF, Line: l.line, Column: l.column, Offset: l.position, EndOffset: l.position}
+		}
+
+		tok := l.scanToken()
+		tok.SourceFile = l.sourceName
+
+		switch l.errorMode {
+		case StrictMode:
+			if len(l.errors) > 0 {
+				l.cacheDone = l.cacheTokens
+				return Token{Type: EOF, Line: tok.Line, Column: tok.Column, Offset: tok.Offset, EndOffset: tok.Offset}
+			}
+		case SilentMode:
+			if tok.Type == ILLEGAL {
+				l.atLineStart = l.atLineStart || tok.FirstOnLine
+				l.afterNewline = l.afterNewline || tok.HasLeadingNewline
+				continue
+			}
+		}
+		l.recordType(tok.Type)
+		if l.cacheTokens {
+			l.cacheToken(tok)
+		}
+		return tok
+	}
+}
+
+// cacheToken keeps a returned token for TokensOnLine. EOF marks the cache
+// complete.
+func (l *Lexer) cacheToken(tok Token) {
+	if tok.Type == EOF {
+		l.cacheDone = true
+		return
+	}
+	l.tokenCache = append(l.tokenCache, tok)
+}
+
+// recordType adds a returned token type to the types AllTokenTypes reports
+func (l *Lexer) recordType(tokType TokenType) {
+	if tokType == EOF {
+		return
+	}
+	if _, seen := l.seenTypeSet[tokType]; seen {
+		return
+	}
+	if l.seenTypeSet == nil {
+		l.seenTypeSet = make(map[TokenType]struct{})
+	}
+	l.seenTypeSet[tokType] = struct{}{}
+	l.seenTypes = append(l.seenTypes, tokType)
+}
+
+// AllTokenTypes returns the distinct token types NextToken has returned so
+// far, excluding EOF, in the order they first appeared. Call it after
+// TokenizeAll to get the types used by the whole input.
+func (l *Lexer) AllTokenTypes() []TokenType {
+	return append([]TokenType(nil), l.seenTypes...)
+}
 
+// scanToken returns the next buffered or scanned token with its EndOffset set
+func (l *Lexer) scanToken() Token {
 	if len(l.tokenBuffer) > 0 {
 		tok := l.tokenBuffer[0]
 		l.tokenBuffer = l.tokenBuffer[1:]
+		l.lastType = tok.Type
 		return tok
 	}
 
+	var tok Token
+	errorCount := len(l.errors)
+	if n := len(l.fstrings); n > 0 && l.fstrings[n-1].depth == 0 {
+		tok = l.readFormatStringPart()
+	} else {
+		tok = l.nextToken()
+		if n > 0 {
+			l.trackFormatBraces(tok)
+		}
+	}
+	if tok.EndOffset == 0 {
+		tok.EndOffset = l.position
+	}
+	if len(l.errors) > errorCount {
+		l.markErrorEnds(l.errors[errorCount:])
+	}
+	tok.FirstOnLine = l.atLineStart
+	tok.HasLeadingNewline = l.afterNewline
+	// Whitespace and comments are skipped or emitted depending on the
+	// options, so they must not change how the following token is read
+	switch {
+	case !isTrivia(tok.Type):
+		l.atLineStart = false
+		l.afterNewline = false
+		l.lastType = tok.Type
+	case strings.IndexByte(l.input[tok.Offset:tok.EndOffset], '\n') >= 0:
+		l.atLineStart = true
+		l.afterNewline = true
+	}
+	return tok
+}
+
+// isTrivia reports whether a token type is whitespace, a newline or a
+// comment, which are only returned when the lexer is asked to emit them
+func isTrivia(tokType TokenType) bool {
+	_, comment := commentTypes[tokType]
+	return comment || tokType == WHITESPACE || tokType == NEWLINE
+}
+
+// newlineToken skips spaces and tabs and returns a NEWLINE token if they
+// are followed by "\n" or "\r\n"
+func (l *Lexer) newlineToken() (Token, bool) {
+	for l.ch == ' ' || l.ch == '\t' || (l.ch == '\r' && l.peekChar() != '\n') {
+		l.readChar()
+	}
+	if l.ch != '\n' && l.ch != '\r' {
+		return Token{}, false
+	}
+
+	offset := l.position
+	line, column := l.charPosition()
+	tok := Token{Type: NEWLINE, Line: line, Column: column, Offset: offset}
+
+	if l.ch == '\r' {
+		l.readChar()
+	}
+	l.readChar()
+	tok.Literal = l.input[offset:l.position]
+	return tok, true
+}
+
+// charPosition returns the line and column of the current character.
+// readChar has already moved the line past a '\n', so a newline is
+// reported at the end of the line it ends.
+func (l *Lexer) charPosition() (int, int) {
+	if l.ch != '\n' {
+		return l.line, l.column
+	}
+	lineStart := strings.LastIndexByte(l.input[:l.position], '\n') + 1
+	return l.line - 1, utf8.RuneCountInString(l.input[lineStart:l.position]) + 1
+}
+
+// nextToken scans a single token. The lexer is left on the first
+// character after the token, which NextToken records as its EndOffset.
+func (l *Lexer) nextToken() Token {
+	var tok Token
+
+	if l.emitWhitespace && (l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r') {
+		offset := l.position
+		line, column := l.charPosition()
+		l.skipWhitespace()
+		return Token{Type: WHITESPACE, Literal: l.input[offset:l.position], Line: line, Column: column, Offset: offset}
+	}
+	if l.emitNewlines {
+		if tok, ok := l.newlineToken(); ok {
+			return tok
+		}
+	}
+	start := l.position
 	l.skipWhitespace()
+	l.markSkipped(start)
 
 	line := l.line
 	column := l.column
+	offset := l.position
+
+	// Registered handlers take precedence over the built-in dispatch
+	if !l.atEOF {
+		if tok, ok := l.tryHandlers(line, column, offset); ok {
+			return tok
+		}
+	}
 
 	// Handle comments FIRST (before operators)
+	if l.commentChar != 0 && l.ch == l.commentChar {
+		l.skipLineComment()
+		if l.emitComments {
+			return Token{Type: LINE_COMMENT, Literal: l.input[offset:l.position], Line: line, Column: column, Offset: offset}
+		}
+		l.markSkipped(offset)
+		return l.nextToken()
+	}
 	if l.ch == '/' {
-		if l.peekChar() == '/' {
+		if l.lineComment && l.peekChar() == '/' {
 			l.skipLineComment()
-			return l.NextToken()
+			if l.emitComments {
+				return l.commentToken(LINE_COMMENT, "///", line, column, offset)
+			}
+			l.markSkipped(offset)
+			return l.nextToken()
 		} else if l.peekChar() == '*' {
 			l.skipBlockComment()
-			return l.NextToken()
+			if l.emitComments {
+				return l.commentToken(BLOCK_COMMENT, "/**", line, column, offset)
+			}
+			l.markSkipped(offset)
+			return l.nextToken()
 		}
 		// If not a comment, fall through to operator handling
 	}
 
+	// Strings with delimiters from a config, before the built-in forms
+	if tok, ok := l.tryCustomString(line, column, offset); ok {
+		return tok
+	}
+
+	// A '$' identifier still cannot start with a digit
+	if l.allowDollar && l.ch == '$' && isDigit(l.peekChar()) {
+		l.addError(CodeInvalidIdentifier, "identifier cannot start with a digit after '$'")
+		l.readChar()
+		return Token{Type: ILLEGAL, Literal: "$", Line: line, Column: column, Offset: offset}
+	}
+
+	// Handle raw strings before identifiers so the r prefix is not read as one
+	if (l.ch == 'r' || l.ch == 'R') && (l.peekChar() == '"' || l.peekChar() == '\'') {
+		l.readChar()
+		str := l.readRawString(l.ch)
+		return Token{Type: RAW_STRING, Literal: str, Line: line, Column: column, Offset: offset}
+	}
+
+	// Handle format strings, whose text is read by readFormatStringPart
+	if (l.ch == 'f' || l.ch == 'F') && (l.peekChar() == '"' || l.peekChar() == '\'') {
+		l.readChar()
+		l.fstrings = append(l.fstrings, formatString{quote: l.ch, line: line, column: column})
+		l.readChar()
+		return Token{Type: FSTRING_START, Literal: l.input[offset:l.position], Line: line, Column: column, Offset: offset}
+	}
+
+	// Handle byte strings, which hold ASCII text and byte escapes
+	if (l.ch == 'b' || l.ch == 'B') && l.peekChar() == '"' {
+		l.readChar()
+		str := l.readByteString(line, column)
+		return Token{Type: BYTE_STRING, Literal: str, Line: line, Column: column, Offset: offset}
+	}
+
 	// Handle identifiers and keywords
-	if isLetter(l.ch) {
+	if l.isIdentStart(l.ch) {
 		literal := l.readIdentifier()
 		if literal == "" {
-			return Token{Type: ILLEGAL, Literal: string(l.ch), Line: line, Column: column}
+			// Unreachable while both checks use isIdentStart, but always
+			// advance so a mismatch cannot stall the lexer
+			ch := l.ch
+			l.readChar()
+			return Token{Type: ILLEGAL, Literal: string(ch), Line: line, Column: column, Offset: offset}
+		}
+		if tok, ok := l.tryMultiWordOperator(literal, line, column, offset); ok {
+			return tok
 		}
 		return Token{
-			Type:    LookupIdent(literal),
+			Type:    l.lookupIdent(literal),
 			Literal: literal,
 			Line:    line,
 			Column:  column,
+			Offset:  offset,
 		}
 	}
 
-	// Handle numbers
-	if isDigit(l.ch) {
+	// Handle numbers, including floats written without a leading zero
+	if isDigit(l.ch) || l.isDotFloat() {
 		errorCountBefore := len(l.errors)
 		literal := l.readNumber()
 
-		// Check if errors were added during number parsing
 		var tokType TokenType = NUMBER
+		switch _, suffix, isFloat := splitNumberSuffix(literal); {
+		case isFloat:
+			tokType = FLOAT
+		case suffix != "" && slices.Contains(l.intSuffixes, suffix):
+			tokType = INT
+		}
+
+		// Check if errors were added during number parsing
 		if len(l.errors) > errorCountBefore {
 			tokType = ILLEGAL
 		}
@@ -932,23 +2226,56 @@ func (l *Lexer) NextToken() Token {
 			Literal: literal,
 			Line:    line,
 			Column:  column,
+			Offset:  offset,
 		}
 	}
 
+	// Handle heredocs: << immediately followed by an identifier tag, where
+	// an operand could start, so a<<b stays a shift
+	if l.ch == '<' && l.peekChar() == '<' && isLetter(l.peekCharN(2)) && !l.afterOperand() {
+		l.readChar() // consume first '<'
+		l.readChar() // consume second '<'
+		tag := l.readIdentifier()
+		body := l.readHeredoc(tag)
+		return Token{Type: HEREDOC, Literal: body, Line: line, Column: column, Offset: offset}
+	}
+
+	// Multi-character punctuation from a config file
+	if tok, ok := l.tryMultiCharToken(line, column, offset); ok {
+		l.readChar()
+		return tok
+	}
+
+	// Annotations such as @decorator
+	if l.annotations && l.ch == '@' && l.isIdentStart(l.peekChar()) {
+		start := l.position
+		l.readChar()
+		l.readIdentifier()
+		return Token{Type: ANNOTATION, Literal: l.input[start:l.position], Line: line, Column: column, Offset: offset}
+	}
+
 	// Try operators
-	if opTok, found := l.tryOperator(line, column); found {
+	if opTok, found := l.tryOperator(line, column, offset); found {
 		l.readChar()
 		return opTok
 	}
 
+	if l.atEOF {
+		return Token{Type: EOF, Literal: "", Line: line, Column: column, Offset: offset}
+	}
+
 	// Handle special cases that need custom logic
 	switch l.ch {
 	case '\'':
 		char := l.readCharLiteral()
-		tok = Token{Type: CHAR, Literal: char, Line: line, Column: column}
+		tok = Token{Type: CHAR, Literal: char, Line: line, Column: column, Offset: offset}
 		// readCharLiteral already consumed the closing quote
 		return tok
 	case '"':
+		if strings.HasPrefix(l.input[l.position:], `"""`) {
+			str := l.readTripleString()
+			return Token{Type: TRIPLE_STRING, Literal: str, Line: line, Column: column, Offset: offset}
+		}
 		str, isInterpolated := l.readString()
 		if isInterpolated {
 			tok = l.tokenBuffer[0]
@@ -960,25 +2287,29 @@ func (l *Lexer) NextToken() Token {
 			Literal: str,
 			Line:    line,
 			Column:  column,
+			Offset:  offset,
 		}
 		return tok
 	case '`':
+		if strings.HasPrefix(l.input[l.position:], "```") {
+			str := l.readTripleBacktick()
+			return Token{Type: TRIPLE_BACKTICK, Literal: str, Line: line, Column: column, Offset: offset}
+		}
 		str := l.readBacktickString()
 		tok = Token{
 			Type:    BACKTICK_STRING,
 			Literal: str,
 			Line:    line,
 			Column:  column,
+			Offset:  offset,
 		}
-	case 0:
-		tok = Token{Type: EOF, Literal: "", Line: line, Column: column}
 	default:
 		// Check single character tokens
-		if tokenType, exists := singleCharTokens[l.ch]; exists {
-			tok = Token{Type: tokenType, Literal: string(l.ch), Line: line, Column: column}
+		if tokenType, exists := l.singleChars[l.ch]; exists {
+			tok = Token{Type: tokenType, Literal: string(l.ch), Line: line, Column: column, Offset: offset}
 		} else {
-			l.addError(fmt.Sprintf("unexpected character '%c' (Unicode: U+%04X)", l.ch, l.ch))
-			tok = Token{Type: ILLEGAL, Literal: string(l.ch), Line: line, Column: column}
+			l.addError(CodeUnexpectedChar, fmt.Sprintf("unexpected character '%c' (Unicode: U+%04X)", l.ch, l.ch))
+			tok = Token{Type: ILLEGAL, Literal: string(l.ch), Line: line, Column: column, Offset: offset}
 		}
 	}
 
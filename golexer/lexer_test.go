@@ -1,62 +1,269 @@
 package golexer
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
+// Test one section of each kind of input
 func TestLexerFullCoverage(t *testing.T) {
-	input := `
-// Keywords
-let const fn if else while for return break continue true false null
+	tests := []struct {
+		name     string
+		input    string
+		expected []TokenType
+	}{
+		{"keywords", "let const fn if else while for return break continue true false null",
+			[]TokenType{LET, CONST, FN, IF, ELSE, WHILE, FOR, RETURN, BREAK, CONTINUE, TRUE, FALSE, NULL}},
+		{"identifiers", "valid_identifier _underscore CamelCase variable123",
+			[]TokenType{IDENT, IDENT, IDENT, IDENT}},
+		{"numbers", "42 3.14 1e10 2.5e-3 1E+5",
+			[]TokenType{NUMBER, FLOAT, FLOAT, FLOAT, FLOAT}},
+		{"strings", `"hello" "world with spaces" "escaped\"quote" "newline\ntest"`,
+			[]TokenType{STRING, STRING, STRING, STRING}},
+		{"chars", `'a' '\n' '\t' '\\' '\''`,
+			[]TokenType{CHAR, CHAR, CHAR, CHAR, CHAR}},
+		{"comments", "let x = 5; // line comment\n/* block comment */ let y = 10;",
+			[]TokenType{LET, IDENT, ASSIGN, NUMBER, SEMICOLON, LET, IDENT, ASSIGN, NUMBER, SEMICOLON}},
+		{"operators", "= + - * / == != < <= > >= += -= *= /= && || !",
+			[]TokenType{ASSIGN, PLUS, MINUS, MULTIPLY, DIVIDE, EQL, NOT_EQL, LESS_THAN, LESS_THAN_EQL,
+				GREATER_THAN, GREATER_THAN_EQL, PLUS_ASSIGN, MINUS_ASSIGN, MULTIPLY_ASSIGN, DIVIDE_ASSIGN, AND, OR, BANG}},
+	}
 
-// Identifiers
-valid_identifier _underscore CamelCase variable123
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, errors := NewLexer(tt.input).TokenizeAll()
+			if len(errors) != 0 {
+				t.Fatalf("Unexpected errors: %v", errors)
+			}
+			if len(tokens) != len(tt.expected) {
+				t.Fatalf("Expected %d tokens, got %v", len(tt.expected), tokens)
+			}
+			for i, tokType := range tt.expected {
+				if tokens[i].Type != tokType {
+					t.Errorf("Token %d: expected %s, got %s", i, tokType, tokens[i].Type)
+				}
+			}
+		})
+	}
 
-// Numbers
-42 3.14 1e10 2.5e-3 1E+5
+	t.Run("errors", func(t *testing.T) {
+		input := "123abc\ninvalid§name\n'\n'\\x'\n\"unterminated\n\"escape\\q\"\n&\n|"
+		tokens, errors := NewLexer(input).TokenizeAll()
+		if len(errors) == 0 {
+			t.Error("Expected errors")
+		}
+		illegal := 0
+		for _, tok := range tokens {
+			if tok.Type == ILLEGAL {
+				illegal++
+			}
+		}
+		if illegal == 0 {
+			t.Errorf("Expected ILLEGAL tokens, got %v", tokens)
+		}
+	})
+}
 
-// Strings
-"hello" "world with spaces" "escaped\"quote" "newline\ntest"
+// Test every built-in keyword
+func TestNextToken_AllKeywords(t *testing.T) {
+	for word, tokType := range defaultKeywords {
+		word, tokType := word, tokType
+		t.Run(word, func(t *testing.T) {
+			l := NewLexer(word)
+			if tok := l.NextToken(); tok.Type != tokType || tok.Literal != word {
+				t.Errorf("Expected %s %q, got %s %q", tokType, word, tok.Type, tok.Literal)
+			}
+			if tok := l.NextToken(); tok.Type != EOF {
+				t.Errorf("Expected EOF, got %s", tok.Type)
+			}
+		})
+	}
+}
 
-// Chars
-'a' '\n' '\t' '\\' '\''
+// Test every form of every built-in operator
+func TestNextToken_AllOperators(t *testing.T) {
+	for _, op := range defaultOperators {
+		for _, form := range op.forms() {
+			if form.literal == "" || form.tokType == "" {
+				continue
+			}
+			form := form
+			t.Run(form.literal, func(t *testing.T) {
+				l := NewLexer("a " + form.literal + " b")
+				l.NextToken()
+				if tok := l.NextToken(); tok.Type != form.tokType || tok.Literal != form.literal {
+					t.Errorf("Expected %s %q, got %s %q", form.tokType, form.literal, tok.Type, tok.Literal)
+				}
+				if tok := l.NextToken(); tok.Type != IDENT {
+					t.Errorf("Expected IDENT after the operator, got %s", tok.Type)
+				}
+			})
+		}
+	}
+}
 
-// Comments
-let x = 5; // line comment
-/* block comment */ let y = 10;
+// Test one example of each literal kind
+func TestNextToken_AllLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected TokenType
+		literal  string
+	}{
+		{"decimal", "42", NUMBER, "42"},
+		{"float", "3.14", FLOAT, "3.14"},
+		{"exponent", "6.02e23", FLOAT, "6.02e23"},
+		{"hex", "0xFF", NUMBER, "0xFF"},
+		{"binary", "0b1010", NUMBER, "0b1010"},
+		{"octal", "0o17", NUMBER, "0o17"},
+		{"string", `"a\tb"`, STRING, "a\tb"},
+		{"char", `'\n'`, CHAR, "\n"},
+		{"backtick", "`a\\tb`", BACKTICK_STRING, `a\tb`},
+		{"triple_string", `"""a"b"""`, TRIPLE_STRING, `a"b`},
+		{"triple_backtick", "```a\nb```", TRIPLE_BACKTICK, "a\nb"},
+		{"raw_string", `r"a\tb"`, RAW_STRING, `a\tb`},
+		{"byte_string", `b"ab"`, BYTE_STRING, "ab"},
+		{"heredoc", "<<END\nbody\nEND", HEREDOC, "body"},
+		{"true", "true", TRUE, "true"},
+		{"null", "null", NULL, "null"},
+	}
 
-// Operators
-= + - * / == != < <= > >= += -= *= /= && || !
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLexer(tt.input)
+			tok := l.NextToken()
+			if tok.Type != tt.expected || tok.Literal != tt.literal {
+				t.Errorf("Input %q: expected %s %q, got %s %q", tt.input, tt.expected, tt.literal, tok.Type, tok.Literal)
+			}
+			if l.HasErrors() {
+				t.Errorf("Input %q: unexpected errors %v", tt.input, l.GetErrors())
+			}
+		})
+	}
+}
 
-// Errors
-123abc
-invalid#name
-'
-'\x'
-"unterminated
-"escape\q"
-&
-|
-`
+// Test the error code reported for each kind of malformed input
+func TestNextToken_ErrorCases(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		code  int
+	}{
+		{"unexpected_char", "§", CodeUnexpectedChar},
+		{"single_ampersand", "&", CodeUnexpectedChar},
+		{"single_pipe", "|", CodeUnexpectedChar},
+		{"letters_after_number", "123abc", CodeInvalidNumber},
+		{"empty_hex", "0x", CodeInvalidNumber},
+		{"empty_binary", "0b", CodeInvalidNumber},
+		{"empty_exponent", "1e+", CodeInvalidNumber},
+		{"unknown_escape", `"\q"`, CodeInvalidEscape},
+		{"short_unicode_escape", `"\u12"`, CodeInvalidEscape},
+		{"unterminated_string", `"abc`, CodeUnterminatedString},
+		{"unterminated_backtick", "`abc", CodeUnterminatedString},
+		{"unterminated_heredoc", "<<END\nx", CodeUnterminatedString},
+		{"unterminated_char", "'a", CodeUnterminatedChar},
+		{"empty_char", "''", CodeUnterminatedChar},
+		{"unterminated_comment", "/* x", CodeUnterminatedComment},
+	}
 
-	lexer := NewLexer(input)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errors := NewLexer(tt.input).TokenizeAll()
+			if len(errors) == 0 {
+				t.Fatalf("Input %q: expected an error", tt.input)
+			}
+			if errors[0].Code != tt.code {
+				t.Errorf("Input %q: expected code %d, got %d (%s)", tt.input, tt.code, errors[0].Code, errors[0].Message)
+			}
+		})
+	}
+}
 
-	for {
-		tok := lexer.NextToken()
-		if tok.Type == EOF {
-			break
+// Test input that produces no tokens
+func TestTokenizeAll_EmptyInput(t *testing.T) {
+	t.Run("tokens", func(t *testing.T) {
+		tokens, errors := NewLexer("").TokenizeAll()
+		if len(tokens) != 0 || len(errors) != 0 {
+			t.Errorf("Expected no tokens or errors, got %v %v", tokens, errors)
 		}
-		if tok.Type == ILLEGAL {
-			t.Logf("Found illegal token: %q", tok.Literal)
-		} else {
-			t.Logf("%s -> %q", tok.Type, tok.Literal)
+	})
+	t.Run("eof", func(t *testing.T) {
+		l := NewLexer("")
+		if tok := l.NextToken(); tok.Type != EOF || tok.Line != 1 || tok.Column != 1 {
+			t.Errorf("Expected EOF at 1:1, got %s at %d:%d", tok.Type, tok.Line, tok.Column)
+		}
+		if tok := l.NextToken(); tok.Type != EOF {
+			t.Errorf("Expected EOF again, got %s", tok.Type)
 		}
+	})
+}
+
+// Test input holding only whitespace
+func TestTokenizeAll_OnlyWhitespace(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"spaces", "   "},
+		{"tabs", "\t\t"},
+		{"newlines", "\n\n\n"},
+		{"crlf", "\r\n\r\n"},
+		{"mixed", " \t\n \r\n\t "},
 	}
 
-	errors := lexer.GetErrors()
-	if len(errors) > 0 {
-		for i, e := range errors {
-			t.Logf("Lexer error[%d]: %s", i, e.Message)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, errors := NewLexer(tt.input).TokenizeAll()
+			if len(tokens) != 0 || len(errors) != 0 {
+				t.Errorf("Input %q: expected no tokens or errors, got %v %v", tt.input, tokens, errors)
+			}
+		})
+	}
+}
+
+// Test input holding only comments
+func TestTokenizeAll_OnlyComments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"line", "// just a comment"},
+		{"line_with_newline", "// one\n// two\n"},
+		{"block", "/* block */"},
+		{"multiline_block", "/*\n * several\n * lines\n */"},
+		{"doc", "/// doc\n/** doc */"},
+		{"mixed", "// a\n/* b */ // c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, errors := NewLexer(tt.input).TokenizeAll()
+			if len(tokens) != 0 || len(errors) != 0 {
+				t.Errorf("Input %q: expected no tokens or errors, got %v %v", tt.input, tokens, errors)
+			}
+			tokens, _ = NewLexer(tt.input, WithEmitComments(true)).TokenizeAll()
+			for _, tok := range tokens {
+				if !tok.IsComment() {
+					t.Errorf("Input %q: expected only comments, got %v", tt.input, tok)
+				}
+			}
+		})
+	}
+}
+
+// Test the shared corpus lexes cleanly with ordered, non-overlapping tokens
+func TestTokenizeAll_Corpus(t *testing.T) {
+	tokens, errors := NewLexer(testCorpus).TokenizeAll()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors[0])
+	}
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].Offset < tokens[i-1].EndOffset {
+			t.Fatalf("Token %d (%v) overlaps the previous token %v", i, tokens[i], tokens[i-1])
 		}
 	}
 }
@@ -75,11 +282,11 @@ func TestNumbers(t *testing.T) {
 		{"0777", NUMBER, "0777"},
 		{"0", NUMBER, "0"},
 		{"999", NUMBER, "999"},
-		{"3.14", NUMBER, "3.14"},
-		{"1e10", NUMBER, "1e10"},
-		{"1E10", NUMBER, "1E10"},
-		{"2.5e-3", NUMBER, "2.5e-3"},
-		{"1.0e+5", NUMBER, "1.0e+5"},
+		{"3.14", FLOAT, "3.14"},
+		{"1e10", FLOAT, "1e10"},
+		{"1E10", FLOAT, "1E10"},
+		{"2.5e-3", FLOAT, "2.5e-3"},
+		{"1.0e+5", FLOAT, "1.0e+5"},
 	}
 
 	for _, tt := range tests {
@@ -95,6 +302,178 @@ func TestNumbers(t *testing.T) {
 	}
 }
 
+// Test floats written without a leading zero
+func TestDotFloats(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Token
+	}{
+		{".5", []Token{{Type: FLOAT, Literal: ".5"}}},
+		{".5e3", []Token{{Type: FLOAT, Literal: ".5e3"}}},
+		{".25e-3", []Token{{Type: FLOAT, Literal: ".25e-3"}}},
+		{"x = .5;", []Token{{Type: IDENT, Literal: "x"}, {Type: ASSIGN, Literal: "="}, {Type: FLOAT, Literal: ".5"}, {Type: SEMICOLON, Literal: ";"}}},
+		{"f(.5)", []Token{{Type: IDENT, Literal: "f"}, {Type: LPAREN, Literal: "("}, {Type: FLOAT, Literal: ".5"}, {Type: RPAREN, Literal: ")"}}},
+		{"a.5", []Token{{Type: IDENT, Literal: "a"}, {Type: DOT, Literal: "."}, {Type: NUMBER, Literal: "5"}}},
+		{"t[0].5", []Token{{Type: IDENT, Literal: "t"}, {Type: LBRACKET, Literal: "["}, {Type: NUMBER, Literal: "0"}, {Type: RBRACKET, Literal: "]"}, {Type: DOT, Literal: "."}, {Type: NUMBER, Literal: "5"}}},
+		{"..5", []Token{{Type: DOT, Literal: "."}, {Type: DOT, Literal: "."}, {Type: NUMBER, Literal: "5"}}},
+		{"1..5", []Token{{Type: NUMBER, Literal: "1"}, {Type: DOT, Literal: "."}, {Type: DOT, Literal: "."}, {Type: NUMBER, Literal: "5"}}},
+		{".x", []Token{{Type: DOT, Literal: "."}, {Type: IDENT, Literal: "x"}}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %v", tt.input, len(tt.expected), tokens)
+			continue
+		}
+		for i, expected := range tt.expected {
+			if tokens[i].Type != expected.Type || tokens[i].Literal != expected.Literal {
+				t.Errorf("Input %q[%d]: expected %s %q, got %s %q", tt.input, i, expected.Type, expected.Literal, tokens[i].Type, tokens[i].Literal)
+			}
+		}
+	}
+
+	// With a ".." range token the dot is taken by the range first
+	rangeConfig := &Config{AdditionalPunctuation: map[string]string{"..": "RANGE"}}
+	tokens, _ := NewLexer("..5", func(l *Lexer) { l.applyConfig(rangeConfig) }).TokenizeAll()
+	if len(tokens) != 2 || tokens[0].Type != "RANGE" || tokens[1].Literal != "5" {
+		t.Errorf("Expected RANGE followed by 5, got %v", tokens)
+	}
+
+	// Emitted whitespace, comments and newlines do not change the result
+	trivia := []struct {
+		input string
+		opt   Option
+	}{
+		{"a .5", WithEmitWhitespace(true)},
+		{"a /*c*/.5", WithEmitComments(true)},
+		{"a\n.5", WithEmitNewlines(true)},
+	}
+	for _, tt := range trivia {
+		var got []TokenType
+		tokens, _ := NewLexer(tt.input, tt.opt).TokenizeAll()
+		for _, tok := range tokens {
+			if !isTrivia(tok.Type) {
+				got = append(got, tok.Type)
+			}
+		}
+		if !reflect.DeepEqual(got, []TokenType{IDENT, DOT, NUMBER}) {
+			t.Errorf("Input %q: expected IDENT DOT NUMBER, got %v", tt.input, got)
+		}
+	}
+}
+
+// Test octal literals with a leading zero or 0o prefix
+func TestOctalLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected TokenType
+		literal  string
+		column   int // column of the error, or 0 for none
+	}{
+		{"0", NUMBER, "0", 0},
+		{"07", NUMBER, "07", 0},
+		{"0777", NUMBER, "0777", 0},
+		{"08", ILLEGAL, "08", 2},
+		{"09", ILLEGAL, "09", 2},
+		{"0778", ILLEGAL, "0778", 4},
+		{"0798", ILLEGAL, "0798", 3},
+		{"0o8", ILLEGAL, "0o8", 3},
+		{"0o78", ILLEGAL, "0o78", 4},
+		{"09.5", FLOAT, "09.5", 0},
+		{"0755e1", FLOAT, "0755e1", 0},
+		{"0.5", FLOAT, "0.5", 0},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		tok := l.NextToken()
+		if tok.Type != tt.expected || tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected %s %q, got %s %q", tt.input, tt.expected, tt.literal, tok.Type, tok.Literal)
+		}
+		if next := l.NextToken(); next.Type != EOF {
+			t.Errorf("Input %q: expected EOF next, got %s %q", tt.input, next.Type, next.Literal)
+		}
+
+		errs := l.GetErrors()
+		if tt.column == 0 {
+			if len(errs) != 0 {
+				t.Errorf("Input %q: unexpected errors %v", tt.input, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 {
+			t.Errorf("Input %q: expected one error, got %v", tt.input, errs)
+			continue
+		}
+		if errs[0].Message != "octal literal cannot contain digits 8 or 9" || errs[0].Column != tt.column {
+			t.Errorf("Input %q: expected the 8 or 9 error at column %d, got %q at column %d", tt.input, tt.column, errs[0].Message, errs[0].Column)
+		}
+	}
+}
+
+// Test underscore digit separators
+func TestNumberSeparators(t *testing.T) {
+	tests := []struct {
+		input   string
+		literal string
+		message string // expected error, or "" for none
+		column  int
+	}{
+		{"1_000_000", "1_000_000", "", 0},
+		{"0xFF_FF", "0xFF_FF", "", 0},
+		{"0b1010_0101", "0b1010_0101", "", 0},
+		{"0o7_7", "0o7_7", "", 0},
+		{"1_000.000_1", "1_000.000_1", "", 0},
+		{"1e1_0", "1e1_0", "", 0},
+		{"1__000", "1__000", "invalid number separator: consecutive underscores", 3},
+		{"0x_FF", "0x_FF", "invalid number separator: digits cannot start with an underscore", 3},
+		{"0b_101", "0b_101", "invalid number separator: digits cannot start with an underscore", 3},
+		{"1_000_", "1_000_", "invalid number separator: number cannot end with an underscore", 6},
+		{"07_8", "07_8", "octal literal cannot contain digits 8 or 9", 4},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input, WithNumberSeparators(true))
+		tok := l.NextToken()
+		if tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected literal %q, got %s %q", tt.input, tt.literal, tok.Type, tok.Literal)
+		}
+		if next := l.NextToken(); next.Type != EOF {
+			t.Errorf("Input %q: expected EOF next, got %s %q", tt.input, next.Type, next.Literal)
+		}
+
+		errs := l.GetErrors()
+		if tt.message == "" {
+			if !tok.IsNumber() || len(errs) != 0 {
+				t.Errorf("Input %q: expected a clean number, got %s with errors %v", tt.input, tok.Type, errs)
+			}
+			continue
+		}
+		if len(errs) != 1 {
+			t.Errorf("Input %q: expected one error, got %v", tt.input, errs)
+			continue
+		}
+		if errs[0].Message != tt.message || errs[0].Column != tt.column {
+			t.Errorf("Input %q: expected %q at column %d, got %q at column %d", tt.input, tt.message, tt.column, errs[0].Message, errs[0].Column)
+		}
+	}
+
+	// A leading underscore starts an identifier, not a number
+	tok := NewLexer("_1", WithNumberSeparators(true)).NextToken()
+	if tok.Type != IDENT || tok.Literal != "_1" {
+		t.Errorf("Input %q: expected IDENT, got %s %q", "_1", tok.Type, tok.Literal)
+	}
+
+	// Separators are off by default
+	if _, errs := NewLexer("1_000").TokenizeAll(); len(errs) == 0 {
+		t.Errorf("Input %q: expected an error without WithNumberSeparators", "1_000")
+	}
+}
+
 // Test invalid numbers
 func TestInvalidNumbers(t *testing.T) {
 	tests := []struct {
@@ -128,6 +507,37 @@ func TestInvalidNumbers(t *testing.T) {
 	}
 }
 
+// Test a malformed exponent is reported once and consumed
+func TestInvalidExponentRecovery(t *testing.T) {
+	tests := []struct {
+		input   string
+		literal string
+		next    TokenType
+	}{
+		{"1e", "1e", EOF},
+		{"1e+", "1e+", EOF},
+		{"1E--", "1E--", EOF},
+		{"1ex", "1ex", EOF},
+		{"2.5e+x1 y", "2.5e+x1", IDENT},
+		{"1e+ 2", "1e+", NUMBER},
+		{"1e;", "1e", SEMICOLON},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		tok := l.NextToken()
+		if tok.Type != ILLEGAL || tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected ILLEGAL %q, got %s %q", tt.input, tt.literal, tok.Type, tok.Literal)
+		}
+		if next := l.NextToken(); next.Type != tt.next {
+			t.Errorf("Input %q: expected %s next, got %s", tt.input, tt.next, next.Type)
+		}
+		if errs := l.GetErrors(); len(errs) != 1 {
+			t.Errorf("Input %q: expected exactly one error, got %v", tt.input, errs)
+		}
+	}
+}
+
 // Test string escapes
 func TestStringEscapes(t *testing.T) {
 	tests := []struct {
@@ -419,6 +829,272 @@ func TestBacktickStrings(t *testing.T) {
 	}
 }
 
+// Test raw strings keep backslashes as written
+func TestRawStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected TokenType
+		literal  string
+	}{
+		{`r"\n"`, RAW_STRING, `\n`},
+		{`R"C:\path\to"`, RAW_STRING, `C:\path\to`},
+		{`r'\d+'`, RAW_STRING, `\d+`},
+		{`r"a\"b"`, RAW_STRING, `a\"b`},
+		{`r"it's"`, RAW_STRING, `it's`},
+		{`r`, IDENT, "r"},
+		{`r + 1`, IDENT, "r"},
+		{`rx"a"`, IDENT, "rx"},
+		{`return`, RETURN, "return"},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expected {
+			t.Errorf("Input %q: expected %s, got %s", tt.input, tt.expected, tok.Type)
+		}
+		if tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.literal, tok.Literal)
+		}
+		if l.HasErrors() {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, l.GetErrors())
+		}
+	}
+}
+
+// Test tokens after a raw string and unterminated raw strings
+func TestRawStringPositions(t *testing.T) {
+	l := NewLexer(`x = r"\t" + y`)
+	expected := []struct {
+		typ    TokenType
+		column int
+	}{
+		{IDENT, 1}, {ASSIGN, 3}, {RAW_STRING, 5}, {PLUS, 11}, {IDENT, 13}, {EOF, 14},
+	}
+	for _, exp := range expected {
+		tok := l.NextToken()
+		if tok.Type != exp.typ || tok.Column != exp.column {
+			t.Errorf("Expected %s at column %d, got %s at column %d", exp.typ, exp.column, tok.Type, tok.Column)
+		}
+	}
+
+	l = NewLexer("a r'abc")
+	l.TokenizeAll()
+	if !l.HasErrors() {
+		t.Fatal("Expected an error for an unterminated raw string")
+	}
+	err := l.GetErrors()[0]
+	if err.Message != "unterminated raw string literal" || err.Line != 1 || err.Column != 3 {
+		t.Errorf("Unexpected error %v at %d:%d", err, err.Line, err.Column)
+	}
+}
+
+// Test byte strings and their ASCII check
+func TestByteStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected TokenType
+		literal  string
+		warning  bool
+	}{
+		{`b"hello"`, BYTE_STRING, "hello", false},
+		{`B"tab\there"`, BYTE_STRING, "tab\there", false},
+		{`b""`, BYTE_STRING, "", false},
+		{`b"héllo"`, BYTE_STRING, "héllo", true},
+		{`b"é"`, BYTE_STRING, "é", true},
+		{`b"\xff\x41\101"`, BYTE_STRING, "\xffAA", false},
+		{`b"\377"`, BYTE_STRING, "\xff", false},
+		{`b"\u00e9"`, BYTE_STRING, "é", false},
+		{`b"a ${x} é"`, BYTE_STRING, "a ${x} é", true},
+		{`b"cost \$5"`, BYTE_STRING, "cost $5", false},
+		{`b`, IDENT, "b", false},
+		{`b + 1`, IDENT, "b", false},
+		{`b'x'`, IDENT, "b", false},
+		{`break`, BREAK, "break", false},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expected {
+			t.Errorf("Input %q: expected %s, got %s", tt.input, tt.expected, tok.Type)
+		}
+		if tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.literal, tok.Literal)
+		}
+		warnings := l.GetWarnings()
+		if tt.warning != (len(warnings) == 1) {
+			t.Errorf("Input %q: expected warning %v, got %v", tt.input, tt.warning, warnings)
+		}
+		if tt.warning && (warnings[0].Code != CodeNonASCIIByte || warnings[0].Column != 1) {
+			t.Errorf("Input %q: unexpected warning %v", tt.input, warnings[0])
+		}
+	}
+
+	tokens, errs := NewLexer(`b"a\777b"`).TokenizeAll()
+	if len(tokens) != 1 || tokens[0].Literal != "ab" || len(errs) != 1 || errs[0].Code != CodeInvalidEscape {
+		t.Errorf("Expected an octal escape over 255 to be reported and dropped, got %v with errors %v", tokens, errs)
+	}
+
+	l := NewLexer(`b"héllo" x`, WithErrorMode(StrictMode))
+	if tok := l.NextToken(); tok.Type != EOF {
+		t.Errorf("Expected strict mode to stop at a non-ASCII byte string, got %s", tok.Type)
+	}
+	if errs := l.GetErrors(); len(errs) != 1 || errs[0].Severity != SeverityError {
+		t.Errorf("Expected one error in strict mode, got %v", errs)
+	}
+}
+
+// Test triple backtick strings
+func TestTripleBacktickStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected TokenType
+		literal  string
+	}{
+		{"```hello\nworld```", TRIPLE_BACKTICK, "hello\nworld"},
+		{"```go\nx := `a`\n```", TRIPLE_BACKTICK, "go\nx := `a`\n"},
+		{"```\\n```", TRIPLE_BACKTICK, "\\n"},
+		{"``````", TRIPLE_BACKTICK, ""},
+		{"`single`", BACKTICK_STRING, "single"},
+		{"``", BACKTICK_STRING, ""},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expected {
+			t.Errorf("Input %q: expected %s, got %s", tt.input, tt.expected, tok.Type)
+		}
+		if tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.literal, tok.Literal)
+		}
+		if next := l.NextToken(); next.Type != EOF {
+			t.Errorf("Input %q: expected EOF after the string, got %s", tt.input, next.Type)
+		}
+		if l.HasErrors() {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, l.GetErrors())
+		}
+	}
+
+	l := NewLexer("```a\nb``` x")
+	l.NextToken()
+	if tok := l.NextToken(); tok.Type != IDENT || tok.Line != 2 || tok.Column != 6 {
+		t.Errorf("Expected IDENT at 2:6, got %s at %d:%d", tok.Type, tok.Line, tok.Column)
+	}
+
+	l = NewLexer("x ```open\n``")
+	l.TokenizeAll()
+	errs := l.GetErrors()
+	if len(errs) != 1 || errs[0].Code != CodeUnterminatedString || errs[0].Line != 1 || errs[0].Column != 3 {
+		t.Errorf("Expected an unterminated error at 1:3, got %v", errs)
+	}
+}
+
+// Test format strings
+func TestFormatStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Token
+	}{
+		{`f"Hello {name}!"`, []Token{
+			{Type: FSTRING_START, Literal: `f"`},
+			{Type: FSTRING_MIDDLE, Literal: "Hello "},
+			{Type: LBRACE, Literal: "{"},
+			{Type: IDENT, Literal: "name"},
+			{Type: RBRACE, Literal: "}"},
+			{Type: FSTRING_MIDDLE, Literal: "!"},
+			{Type: FSTRING_END, Literal: `"`},
+		}},
+		{`f"{f'{x}'}"`, []Token{
+			{Type: FSTRING_START, Literal: `f"`},
+			{Type: LBRACE, Literal: "{"},
+			{Type: FSTRING_START, Literal: `f'`},
+			{Type: LBRACE, Literal: "{"},
+			{Type: IDENT, Literal: "x"},
+			{Type: RBRACE, Literal: "}"},
+			{Type: FSTRING_END, Literal: `'`},
+			{Type: RBRACE, Literal: "}"},
+			{Type: FSTRING_END, Literal: `"`},
+		}},
+		{`f"{{literal}}"`, []Token{
+			{Type: FSTRING_START, Literal: `f"`},
+			{Type: FSTRING_MIDDLE, Literal: "{literal}"},
+			{Type: FSTRING_END, Literal: `"`},
+		}},
+		{`f"{ {a: 1}[a] }\n"`, []Token{
+			{Type: FSTRING_START, Literal: `f"`},
+			{Type: LBRACE, Literal: "{"},
+			{Type: LBRACE, Literal: "{"},
+			{Type: IDENT, Literal: "a"},
+			{Type: COLON, Literal: ":"},
+			{Type: NUMBER, Literal: "1"},
+			{Type: RBRACE, Literal: "}"},
+			{Type: LBRACKET, Literal: "["},
+			{Type: IDENT, Literal: "a"},
+			{Type: RBRACKET, Literal: "]"},
+			{Type: RBRACE, Literal: "}"},
+			{Type: FSTRING_MIDDLE, Literal: "\n"},
+			{Type: FSTRING_END, Literal: `"`},
+		}},
+		{`f"" f`, []Token{
+			{Type: FSTRING_START, Literal: `f"`},
+			{Type: FSTRING_END, Literal: `"`},
+			{Type: IDENT, Literal: "f"},
+		}},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		for i, exp := range tt.expected {
+			tok := l.NextToken()
+			if tok.Type != exp.Type || tok.Literal != exp.Literal {
+				t.Errorf("Input %q token %d: expected %s %q, got %s %q", tt.input, i, exp.Type, exp.Literal, tok.Type, tok.Literal)
+			}
+		}
+		if tok := l.NextToken(); tok.Type != EOF {
+			t.Errorf("Input %q: expected EOF, got %s", tt.input, tok.Type)
+		}
+		if l.HasErrors() {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, l.GetErrors())
+		}
+	}
+}
+
+// Test unterminated format strings report the opening quote
+func TestUnterminatedFormatString(t *testing.T) {
+	inputs := []string{`x f"abc`, `x f"a {b`, `x f"{f'{y}`}
+
+	for _, input := range inputs {
+		l := NewLexer(input)
+		l.TokenizeAll()
+		errs := l.GetErrors()
+		if len(errs) != 1 {
+			t.Errorf("Input %q: expected one error, got %v", input, errs)
+			continue
+		}
+		if errs[0].Message != "unterminated format string literal" || errs[0].Column != 3 {
+			t.Errorf("Input %q: unexpected error %v at column %d", input, errs[0], errs[0].Column)
+		}
+	}
+}
+
+// Test a single closing brace in format string text is reported
+func TestFormatStringLoneBrace(t *testing.T) {
+	l := NewLexer(`f"a } b"`)
+	tokens, errs := l.TokenizeAll()
+
+	if len(tokens) != 3 || tokens[1].Type != FSTRING_MIDDLE || tokens[1].Literal != "a } b" {
+		t.Errorf("Expected the brace to stay in the text, got %v", tokens)
+	}
+	if len(errs) != 1 || errs[0].Code != CodeUnexpectedChar || errs[0].Column != 5 {
+		t.Errorf("Expected one error at column 5, got %v", errs)
+	}
+}
+
 // Test position tracking
 func TestPositionTracking(t *testing.T) {
 	input := "x y\nz"
@@ -498,7 +1174,6 @@ func TestConfigAdditionalKeywords(t *testing.T) {
 }
 
 // Test config loading with additional punctuation
-// Note: Config merges to global state, so once loaded it persists
 func TestConfigAdditionalPunctuation(t *testing.T) {
 	// Load config and test punctuation tokens
 	lexer := NewLexerWithConfig("@ # $", "../examples/config.json")
@@ -573,3 +1248,1413 @@ func TestErrorCollection(t *testing.T) {
 		}
 	}
 }
+
+// Test Rewind restarts from the beginning of the same input
+func TestRewind(t *testing.T) {
+	input := "let x = 123abc;\nlet s = \"a ${x} b\";\nf\"{x}\" § \"unterminated"
+	l := NewLexer(input)
+
+	first, errors := l.TokenizeAll()
+	if len(errors) == 0 {
+		t.Fatalf("Expected errors on the first pass")
+	}
+	firstErrors := append(LexErrorSlice(nil), errors...)
+
+	l.Rewind()
+	if l.HasErrors() {
+		t.Errorf("Expected Rewind to clear errors, got %v", l.GetErrors())
+	}
+	second, errors := l.TokenizeAll()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same tokens after Rewind:\nfirst:  %v\nsecond: %v", first, second)
+	}
+	if !reflect.DeepEqual(firstErrors, errors) {
+		t.Errorf("Expected the same errors after Rewind, got %v, want %v", errors, firstErrors)
+	}
+
+	// Rewinding part way through, inside a format string, starts over too
+	l.Rewind()
+	for i := 0; i < 12; i++ {
+		l.NextToken()
+	}
+	l.Rewind()
+	if tok := l.NextToken(); tok.Type != LET || tok.Line != 1 || tok.Column != 1 || tok.Offset != 0 {
+		t.Errorf("Expected LET at 1:1 after Rewind, got %s %q at %d:%d", tok.Type, tok.Literal, tok.Line, tok.Column)
+	}
+
+	// The size limit still applies after Rewind
+	l = NewLexer("let x = 1;", WithMaxInputSize(4))
+	l.Rewind()
+	if errs := l.GetErrors(); len(errs) != 1 || errs[0].Code != CodeInputTooLarge {
+		t.Errorf("Expected the input size error to survive Rewind, got %v", errs)
+	}
+}
+
+// Test AllTokenTypes lists each type once in first-seen order
+func TestAllTokenTypes(t *testing.T) {
+	l := NewLexer("let x = 1;\nlet y = x;\nx = 2;")
+	if types := l.AllTokenTypes(); len(types) != 0 {
+		t.Errorf("Expected no types before lexing, got %v", types)
+	}
+
+	l.TokenizeAll()
+	expected := []TokenType{LET, IDENT, ASSIGN, NUMBER, SEMICOLON}
+	first := l.AllTokenTypes()
+	if !reflect.DeepEqual(first, expected) {
+		t.Errorf("Expected %v, got %v", expected, first)
+	}
+
+	// A second pass after Rewind sees the same types
+	l.Rewind()
+	if types := l.AllTokenTypes(); len(types) != 0 {
+		t.Errorf("Expected Rewind to clear the types, got %v", types)
+	}
+	l.TokenizeAll()
+	if second := l.AllTokenTypes(); !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected %v after Rewind, got %v", first, second)
+	}
+
+	// Dropped tokens are not counted, and the result is a copy
+	l = NewLexer("a § b", WithErrorMode(SilentMode))
+	l.TokenizeAll()
+	types := l.AllTokenTypes()
+	if !reflect.DeepEqual(types, []TokenType{IDENT}) {
+		t.Errorf("Expected only IDENT in silent mode, got %v", types)
+	}
+	types[0] = NUMBER
+	if l.AllTokenTypes()[0] != IDENT {
+		t.Errorf("Expected AllTokenTypes to return a copy")
+	}
+}
+
+// Test ScanOnly reports the same errors as TokenizeAll
+func TestScanOnly(t *testing.T) {
+	input := "let x = 123abc; \"unterminated"
+
+	_, expected := NewLexer(input).TokenizeAll()
+	got := ScanOnly(input)
+
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i].Error() != expected[i].Error() {
+			t.Errorf("Error %d: expected %q, got %q", i, expected[i].Error(), got[i].Error())
+		}
+	}
+}
+
+// Test ValidateSyntax
+func TestValidateSyntax(t *testing.T) {
+	if err := ValidateSyntax("let x = 5;"); err != nil {
+		t.Errorf("Expected nil for valid input, got %v", err)
+	}
+
+	err := ValidateSyntax("let x = 0xZZ; let y = &;")
+	if err == nil {
+		t.Fatalf("Expected error for invalid input")
+	}
+	if !strings.Contains(err.Error(), "hexadecimal") || !strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("Expected combined error to list every issue, got %q", err.Error())
+	}
+}
+
+func BenchmarkScanOnly(b *testing.B) {
+	input := strings.Repeat("let x = 10 + y * 3.14; // comment\n", 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ScanOnly(input)
+	}
+}
+
+func BenchmarkTokenizeAllForValidation(b *testing.B) {
+	input := strings.Repeat("let x = 10 + y * 3.14; // comment\n", 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewLexer(input).TokenizeAll()
+	}
+}
+
+// Test byte offsets point at the start of each token
+func TestTokenOffsets(t *testing.T) {
+	input := "let é = \"s\";\n  x >= 0x1F"
+	tokens, _ := NewLexer(input).TokenizeAll()
+
+	for _, tok := range tokens {
+		if tok.Offset < 0 || tok.Offset >= len(input) {
+			t.Fatalf("Token %q: offset %d out of range", tok.Literal, tok.Offset)
+		}
+		if tok.Type == STRING {
+			if input[tok.Offset] != '"' {
+				t.Errorf("STRING token: expected offset at opening quote, got %q", input[tok.Offset])
+			}
+			continue
+		}
+		if !strings.HasPrefix(input[tok.Offset:], tok.Literal) {
+			t.Errorf("Token %q: offset %d points at %q", tok.Literal, tok.Offset, input[tok.Offset:])
+		}
+	}
+}
+
+// Test the maximum input size option
+func TestMaxInputSize(t *testing.T) {
+	input := strings.Repeat("x ", 100)
+
+	lexer := NewLexer(input, WithMaxInputSize(100))
+	if !lexer.HasErrors() {
+		t.Fatalf("Expected error for input over the size limit")
+	}
+	if !strings.Contains(lexer.GetErrors()[0].Message, "maximum input size") {
+		t.Errorf("Expected error to mention the size limit, got %q", lexer.GetErrors()[0].Message)
+	}
+	for i := 0; i < 3; i++ {
+		if tok := lexer.NextToken(); tok.Type != EOF {
+			t.Errorf("Expected EOF from oversized input, got %s", tok.Type)
+		}
+	}
+
+	unlimited := NewLexer(input, WithMaxInputSize(0))
+	tokens, errors := unlimited.TokenizeAll()
+	if len(errors) != 0 || len(tokens) != 100 {
+		t.Errorf("Expected zero limit to mean unlimited, got %d tokens and %d errors", len(tokens), len(errors))
+	}
+}
+
+// Test NewLexerSafe
+func TestNewLexerSafe(t *testing.T) {
+	if _, err := NewLexerSafe(strings.Repeat("a", 200), WithMaxInputSize(100)); err == nil {
+		t.Errorf("Expected error for oversized input")
+	}
+
+	lexer, err := NewLexerSafe("let x = 1;", WithMaxInputSize(100))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if tok := lexer.NextToken(); tok.Type != LET {
+		t.Errorf("Expected LET, got %s", tok.Type)
+	}
+}
+
+// Test Unicode escape sequences
+func TestUnicodeEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"\u0041"`, "A"},
+		{`"\u00e9t\u00E9"`, "été"},
+		{`"\U0001F600"`, "😀"},
+		{`"\u{1F600}"`, "😀"},
+		{`"\u{41}BC"`, "ABC"},
+		{`'\u20AC'`, "€"},
+	}
+
+	for _, tt := range tests {
+		lexer := NewLexer(tt.input)
+		tok := lexer.NextToken()
+
+		if tok.Literal != tt.expected {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.expected, tok.Literal)
+		}
+		if lexer.HasErrors() {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, lexer.GetErrors())
+		}
+	}
+}
+
+// Test invalid Unicode escape sequences
+func TestInvalidUnicodeEscapes(t *testing.T) {
+	tests := []string{
+		`"\uD800"`,      // surrogate
+		`"\U00110000"`,  // beyond U+10FFFF
+		`"\u12"`,        // too few digits
+		`"\u{}"`,        // empty braces
+		`"\u{1234567}"`, // too many digits
+		`"\u{12"`,       // unclosed braces
+	}
+
+	for _, input := range tests {
+		lexer := NewLexer(input)
+		lexer.TokenizeAll()
+		if !lexer.HasErrors() {
+			t.Errorf("Input %q: expected error", input)
+		}
+	}
+}
+
+// Test triple-quoted strings
+func TestTripleStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"""hello"""`, "hello"},
+		{"\"\"\"line one\nline two\"\"\"", "line one\nline two"},
+		{`"""say "hi" now"""`, `say "hi" now`},
+		{`"""tab\there"""`, "tab\there"},
+		{`""""""`, ""},
+		{`"""""""`, `"`},
+		{`"""a""""`, `a"`},
+	}
+
+	for _, tt := range tests {
+		lexer := NewLexer(tt.input)
+		tok := lexer.NextToken()
+
+		if tok.Type != TRIPLE_STRING {
+			t.Errorf("Input %q: expected TRIPLE_STRING, got %s", tt.input, tok.Type)
+		}
+		if tok.Literal != tt.expected {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.expected, tok.Literal)
+		}
+		if next := lexer.NextToken(); next.Type != EOF {
+			t.Errorf("Input %q: expected EOF after string, got %s", tt.input, next.Type)
+		}
+	}
+}
+
+// Test triple-quoted strings next to regular strings
+func TestTripleStringBoundaries(t *testing.T) {
+	tokens, errors := NewLexer(`"" x """y""" "z"`).TokenizeAll()
+	expected := []TokenType{STRING, IDENT, TRIPLE_STRING, STRING}
+
+	if len(errors) != 0 {
+		t.Errorf("Unexpected errors: %v", errors)
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d", len(expected), len(tokens))
+	}
+	for i, tt := range expected {
+		if tokens[i].Type != tt {
+			t.Errorf("Token %d: expected %s, got %s", i, tt, tokens[i].Type)
+		}
+	}
+
+	lexer := NewLexer(`"""never closed`)
+	lexer.TokenizeAll()
+	if !lexer.HasErrors() {
+		t.Errorf("Expected error for unterminated triple-quoted string")
+	}
+}
+
+// Test heredoc strings
+func TestHeredoc(t *testing.T) {
+	input := "x = <<END\nhello\nworld\nEND\ny"
+	lexer := NewLexer(input)
+
+	expected := []struct {
+		tokType TokenType
+		literal string
+		line    int
+	}{
+		{IDENT, "x", 1},
+		{ASSIGN, "=", 1},
+		{HEREDOC, "hello\nworld", 1},
+		{IDENT, "y", 5},
+		{EOF, "", 5},
+	}
+
+	for i, tt := range expected {
+		tok := lexer.NextToken()
+		if tok.Type != tt.tokType || tok.Literal != tt.literal || tok.Line != tt.line {
+			t.Errorf("Token %d: expected %s %q on line %d, got %s %q on line %d",
+				i, tt.tokType, tt.literal, tt.line, tok.Type, tok.Literal, tok.Line)
+		}
+	}
+	if lexer.HasErrors() {
+		t.Errorf("Unexpected errors: %v", lexer.GetErrors())
+	}
+}
+
+// Test heredoc edge cases
+func TestHeredocEdgeCases(t *testing.T) {
+	// Terminator may be followed by whitespace; other text on the line is content
+	lexer := NewLexer("<<EOT\n  EOT is here\nEOT  \n")
+	tok := lexer.NextToken()
+	if tok.Type != HEREDOC || tok.Literal != "  EOT is here" {
+		t.Errorf("Expected HEREDOC %q, got %s %q", "  EOT is here", tok.Type, tok.Literal)
+	}
+
+	// Shift-like usage without a tag is not a heredoc
+	tokens, _ := NewLexer("a << b").TokenizeAll()
+	for _, tok := range tokens {
+		if tok.Type == HEREDOC {
+			t.Errorf("Expected no HEREDOC for spaced <<")
+		}
+	}
+
+	unterminated := NewLexer("x = <<END\nhello\n")
+	unterminated.TokenizeAll()
+	if !unterminated.HasErrors() {
+		t.Errorf("Expected error for unterminated heredoc")
+	}
+
+	// Tokens after the tag on the opening line are lexed, then the body is skipped
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected []string // type:literal:line
+	}{
+		{"x = <<END; y\nbody\nEND\nz", nil,
+			[]string{"IDENT:x:1", "ASSIGN:=:1", "HEREDOC:body:1", "SEMICOLON:;:1", "IDENT:y:1", "IDENT:z:4"}},
+		{"x = <<END // note\nbody\nEND\nz", nil,
+			[]string{"IDENT:x:1", "ASSIGN:=:1", "HEREDOC:body:1", "IDENT:z:4"}},
+		{"f(<<A, <<B)\none\nA\ntwo\nB\nend", nil,
+			[]string{"IDENT:f:1", "LPAREN:(:1", "HEREDOC:one:1", "COMMA:,:1", "HEREDOC:two:1", "RPAREN:):1", "IDENT:end:6"}},
+		{"a<<b", nil,
+			[]string{"IDENT:a:1", "LEFT_SHIFT:<<:1", "IDENT:b:1"}},
+		{"f(x)<<END", nil,
+			[]string{"IDENT:f:1", "LPAREN:(:1", "IDENT:x:1", "RPAREN:):1", "LEFT_SHIFT:<<:1", "IDENT:END:1"}},
+		{"x = <<END not\nbody\nEND\nin", []Option{WithMultiWordOperators([]MultiWordOperator{{Words: []string{"not", "in"}, Type: "NOT_IN"}})},
+			[]string{"IDENT:x:1", "ASSIGN:=:1", "HEREDOC:body:1", "IDENT:not:1", "IN:in:4"}},
+	}
+	for _, tt := range tests {
+		tokens, errs := NewLexer(tt.input, tt.opts...).TokenizeAll()
+		if len(errs) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errs)
+		}
+		got := make([]string, len(tokens))
+		for i, tok := range tokens {
+			got[i] = fmt.Sprintf("%s:%s:%d", tok.Type, tok.Literal, tok.Line)
+		}
+		if strings.Join(got, " ") != strings.Join(tt.expected, " ") {
+			t.Errorf("Input %q: expected %v, got %v", tt.input, tt.expected, got)
+		}
+	}
+}
+
+// Test multi-word operators
+func TestMultiWordOperators(t *testing.T) {
+	opts := WithMultiWordOperators([]MultiWordOperator{
+		{Words: []string{"not", "in"}, Type: "NOT_IN"},
+		{Words: []string{"is", "not"}, Type: "IS_NOT"},
+		{Words: []string{"is"}, Type: "IS"},
+	})
+
+	tests := []struct {
+		input    string
+		expected []TokenType
+		literals []string
+	}{
+		{"x not in y", []TokenType{IDENT, "NOT_IN", IDENT}, []string{"x", "not in", "y"}},
+		{"x not\tin y", []TokenType{IDENT, "NOT_IN", IDENT}, []string{"x", "not in", "y"}},
+		{"a is not b", []TokenType{IDENT, "IS_NOT", IDENT}, []string{"a", "is not", "b"}},
+		{"not x", []TokenType{IDENT, IDENT}, []string{"not", "x"}},
+		{"x not inside", []TokenType{IDENT, IDENT, IDENT}, []string{"x", "not", "inside"}},
+		{"not\nin", []TokenType{IDENT, IN}, []string{"not", "in"}},
+		{"is", []TokenType{IDENT}, []string{"is"}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input, opts).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %d", tt.input, len(tt.expected), len(tokens))
+			continue
+		}
+		for i := range tokens {
+			if tokens[i].Type != tt.expected[i] || tokens[i].Literal != tt.literals[i] {
+				t.Errorf("Input %q[%d]: expected %s %q, got %s %q",
+					tt.input, i, tt.expected[i], tt.literals[i], tokens[i].Type, tokens[i].Literal)
+			}
+		}
+	}
+
+	// Without the option the words stay separate
+	tokens, _ := NewLexer("x not in y").TokenizeAll()
+	if len(tokens) != 4 {
+		t.Errorf("Expected 4 tokens without multi-word operators, got %d", len(tokens))
+	}
+}
+
+// Test the three-way comparison operator
+func TestSpaceshipOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []TokenType
+	}{
+		{"a <=> b", []TokenType{IDENT, SPACESHIP, IDENT}},
+		{"<=>x", []TokenType{SPACESHIP, IDENT}},
+		{"a <= b", []TokenType{IDENT, LESS_THAN_EQL, IDENT}},
+		{"a <= > b", []TokenType{IDENT, LESS_THAN_EQL, GREATER_THAN, IDENT}},
+		{"a < => b", []TokenType{IDENT, LESS_THAN, ASSIGN, GREATER_THAN, IDENT}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %d", tt.input, len(tt.expected), len(tokens))
+			continue
+		}
+		for i, expectedType := range tt.expected {
+			if tokens[i].Type != expectedType {
+				t.Errorf("Input %q[%d]: expected %s, got %s", tt.input, i, expectedType, tokens[i].Type)
+			}
+		}
+	}
+}
+
+// Test multi-character lookahead
+func TestPeekCharN(t *testing.T) {
+	lexer := NewLexer("aé€b")
+	expected := []rune{'é', '€', 'b', 0, 0}
+	for i, r := range expected {
+		if got := lexer.peekCharN(i + 1); got != r {
+			t.Errorf("peekCharN(%d): expected %q, got %q", i+1, r, got)
+		}
+	}
+	if lexer.peekCharN(1) != lexer.peekChar() {
+		t.Errorf("Expected peekCharN(1) to match peekChar")
+	}
+}
+
+// Test pattern matching operators
+func TestMatchOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []TokenType
+	}{
+		{`str ~= pattern`, []TokenType{IDENT, TILDE_ASSIGN, IDENT}},
+		{`"abc" ~= "a.c"`, []TokenType{STRING, TILDE_ASSIGN, STRING}},
+		{`str =~ pattern`, []TokenType{IDENT, MATCH_ASSIGN, IDENT}},
+		{`~`, []TokenType{TILDE}},
+		{`a == b`, []TokenType{IDENT, EQL, IDENT}},
+		{`a = b`, []TokenType{IDENT, ASSIGN, IDENT}},
+		{`a ~ = b`, []TokenType{IDENT, TILDE, ASSIGN, IDENT}},
+		{`~x`, []TokenType{TILDE, IDENT}},
+		{`~0xFF`, []TokenType{TILDE, NUMBER}},
+		{`~~x`, []TokenType{TILDE, TILDE, IDENT}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %d", tt.input, len(tt.expected), len(tokens))
+			continue
+		}
+		for i, expectedType := range tt.expected {
+			if tokens[i].Type != expectedType {
+				t.Errorf("Input %q[%d]: expected %s, got %s", tt.input, i, expectedType, tokens[i].Type)
+			}
+		}
+	}
+}
+
+// Test left and right shift operators
+func TestShiftOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []TokenType
+	}{
+		{"n << 1", []TokenType{IDENT, LEFT_SHIFT, NUMBER}},
+		{"n <<= 1", []TokenType{IDENT, LEFT_SHIFT_ASSIGN, NUMBER}},
+		{"n<<1", []TokenType{IDENT, LEFT_SHIFT, NUMBER}},
+		{"n <= 1", []TokenType{IDENT, LESS_THAN_EQL, NUMBER}},
+		{"a <=> b", []TokenType{IDENT, SPACESHIP, IDENT}},
+		{"n >>> 1", []TokenType{IDENT, UNSIGNED_RIGHT_SHIFT, NUMBER}},
+		{"n >>>= 1", []TokenType{IDENT, UNSIGNED_RIGHT_SHIFT_ASSIGN, NUMBER}},
+		{"n >> 1", []TokenType{IDENT, RIGHT_SHIFT, NUMBER}},
+		{"n >>= 1", []TokenType{IDENT, RIGHT_SHIFT_ASSIGN, NUMBER}},
+		{"n >= 1", []TokenType{IDENT, GREATER_THAN_EQL, NUMBER}},
+		{"n > > 1", []TokenType{IDENT, GREATER_THAN, GREATER_THAN, NUMBER}},
+		{"n>>>1", []TokenType{IDENT, UNSIGNED_RIGHT_SHIFT, NUMBER}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %d", tt.input, len(tt.expected), len(tokens))
+			continue
+		}
+		for i, expectedType := range tt.expected {
+			if tokens[i].Type != expectedType {
+				t.Errorf("Input %q[%d]: expected %s, got %s", tt.input, i, expectedType, tokens[i].Type)
+			}
+		}
+	}
+
+	if LEFT_SHIFT.Precedence() != RIGHT_SHIFT.Precedence() || !LEFT_SHIFT_ASSIGN.IsRightAssociative() {
+		t.Errorf("Expected << to bind like >> and <<= to be right associative")
+	}
+	if RIGHT_SHIFT.Precedence() <= LESS_THAN.Precedence() || RIGHT_SHIFT.Precedence() >= PLUS.Precedence() {
+		t.Errorf("Expected shifts to bind between comparison and addition")
+	}
+}
+
+// Test that operator priority decides between overlapping literals
+func TestOperatorPriority(t *testing.T) {
+	restorePackageTables(t)
+
+	// Longest literal wins at equal priority
+	operators = []Operator{
+		{">>", RIGHT_SHIFT, "", "", 0},
+		{">>>", UNSIGNED_RIGHT_SHIFT, "", "", 0},
+	}
+	SortOperators()
+	tokens, _ := NewLexer(">>>").TokenizeAll()
+	if len(tokens) != 1 || tokens[0].Type != UNSIGNED_RIGHT_SHIFT {
+		t.Errorf("Expected a single UNSIGNED_RIGHT_SHIFT, got %v", tokens)
+	}
+
+	// Higher priority wins over a longer literal
+	operators = []Operator{
+		{">>", RIGHT_SHIFT, "", "", 1},
+		{">>>", UNSIGNED_RIGHT_SHIFT, "", "", 0},
+	}
+	SortOperators()
+	tokens, _ = NewLexer(">>>").TokenizeAll()
+	if len(tokens) == 0 || tokens[0].Type != RIGHT_SHIFT || tokens[0].Literal != ">>" {
+		t.Errorf("Expected RIGHT_SHIFT to win by priority, got %v", tokens)
+	}
+	if operators[0].Single != ">>" {
+		t.Errorf("Expected SortOperators to put the higher priority first, got %q", operators[0].Single)
+	}
+}
+
+// Test operator table validation
+func TestValidateOperatorTable(t *testing.T) {
+	if warnings := validateOperatorTable(operators); len(warnings) != 0 {
+		t.Errorf("Expected built-in table to be unambiguous, got %v", warnings)
+	}
+
+	warnings := validateOperatorTable([]Operator{
+		{"**", "POWER", "", "", 0},
+		{"**", "EXPONENT", "", "", 0},
+		{"**", "STAR_STAR", "", "", 1},
+	})
+	if len(warnings) != 1 {
+		t.Errorf("Expected 1 warning, got %v", warnings)
+	}
+
+	// Config operators are checked against the table they join
+	var applied []string
+	NewLexer("a + b", func(l *Lexer) {
+		applied = l.applyConfig(&Config{AdditionalOperators: map[string]string{"+": "ADD"}})
+	})
+	if len(applied) != 1 || !strings.Contains(applied[0], "'+'") {
+		t.Errorf("Expected a warning for the redefined '+', got %v", applied)
+	}
+}
+
+// Test whitespace tokens emitted with WithEmitWhitespace
+func TestEmitWhitespace(t *testing.T) {
+	input := "a  b\n\t c"
+	tokens, _ := NewLexer(input, WithEmitWhitespace(true)).TokenizeAll()
+
+	expected := []Token{
+		NewToken(IDENT, "a", 1, 1),
+		NewToken(WHITESPACE, "  ", 1, 2),
+		NewToken(IDENT, "b", 1, 4),
+		NewToken(WHITESPACE, "\n\t ", 1, 5),
+		NewToken(IDENT, "c", 2, 3),
+	}
+	if !TokenSliceEqual(tokens, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+
+	var rebuilt strings.Builder
+	for _, tok := range tokens {
+		rebuilt.WriteString(tok.SourceText(input))
+	}
+	if rebuilt.String() != input {
+		t.Errorf("Expected tokens to cover the input, got %q", rebuilt.String())
+	}
+
+	// A run starting with a newline is reported on the line it ends
+	for _, tt := range []struct {
+		input        string
+		index        int
+		line, column int
+	}{
+		{"x = 1\n  y", 5, 1, 6},
+		{"a  b\n\t c", 3, 1, 5},
+		{"\n\nz", 0, 1, 1},
+		{"é\r\n z", 1, 1, 2},
+	} {
+		tokens, _ := NewLexer(tt.input, WithEmitWhitespace(true)).TokenizeAll()
+		if len(tokens) <= tt.index || tokens[tt.index].Type != WHITESPACE {
+			t.Errorf("Input %q: expected WHITESPACE at index %d, got %v", tt.input, tt.index, tokens)
+			continue
+		}
+		if tok := tokens[tt.index]; tok.Line != tt.line || tok.Column != tt.column {
+			t.Errorf("Input %q: expected WHITESPACE at %d:%d, got %d:%d", tt.input, tt.line, tt.column, tok.Line, tok.Column)
+		}
+	}
+}
+
+// Test the strict, lenient and silent error modes
+func TestErrorModes(t *testing.T) {
+	input := "let x = 5 § y;\nlet z = 123abc;"
+
+	tokens, errors := NewLexer(input, WithErrorMode(StrictMode)).TokenizeAll()
+	if len(errors) != 1 {
+		t.Errorf("StrictMode: expected 1 error, got %d", len(errors))
+	}
+	expected := []TokenType{LET, IDENT, ASSIGN, NUMBER}
+	if len(tokens) != len(expected) {
+		t.Fatalf("StrictMode: expected tokenization to stop at '§', got %v", tokens)
+	}
+	l := NewLexer(input, WithErrorMode(StrictMode))
+	for i := 0; i < 4; i++ {
+		l.NextToken()
+	}
+	for i := 0; i < 3; i++ {
+		if tok := l.NextToken(); tok.Type != EOF {
+			t.Errorf("StrictMode: expected EOF after an error, got %s", tok.Type)
+		}
+	}
+
+	tokens, errors = NewLexer(input).TokenizeAll()
+	if len(errors) != 2 || len(tokens) != 12 {
+		t.Errorf("LenientMode: expected 2 errors and 12 tokens, got %d and %d", len(errors), len(tokens))
+	}
+
+	l = NewLexer(input, WithErrorMode(SilentMode))
+	tokens, errors = l.TokenizeAll()
+	if len(errors) != 0 || len(l.GetErrors()) != 0 || l.HasErrors() {
+		t.Errorf("SilentMode: expected no errors, got %v", errors)
+	}
+	for _, tok := range tokens {
+		if tok.Type == ILLEGAL {
+			t.Errorf("SilentMode: unexpected ILLEGAL token %q", tok.Literal)
+		}
+	}
+	if len(tokens) != 11 || tokens[4].Type != IDENT || tokens[9].Type != NUMBER {
+		t.Errorf("SilentMode: expected '§' dropped and 123abc kept as NUMBER, got %v", tokens)
+	}
+}
+
+// Test multi-character punctuation from a config file
+func TestConfigMultiCharPunctuation(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{"additionalPunctuation": {"@>": "FISH", "::": "NAMESPACE", ":::": "TRIPLE_COLON"}}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, errors := NewLexerWithConfig("a @> b::c:::d:e", configFile).TokenizeAll()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	expected := []Token{
+		NewToken(IDENT, "a", 1, 1),
+		NewToken("FISH", "@>", 1, 3),
+		NewToken(IDENT, "b", 1, 6),
+		NewToken("NAMESPACE", "::", 1, 7),
+		NewToken(IDENT, "c", 1, 9),
+		NewToken("TRIPLE_COLON", ":::", 1, 10),
+		NewToken(IDENT, "d", 1, 13),
+		NewToken(COLON, ":", 1, 14),
+		NewToken(IDENT, "e", 1, 15),
+	}
+	if !TokenSliceEqual(tokens, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+	for i := range expected {
+		if tokens[i].Column != expected[i].Column {
+			t.Errorf("Token %d: expected column %d, got %d", i, expected[i].Column, tokens[i].Column)
+		}
+	}
+
+	// Multi-character punctuation belongs to the lexer that loaded it
+	tokens, _ = NewLexer("b::c").TokenizeAll()
+	if len(tokens) != 4 {
+		t.Errorf("Expected '::' to be two colons without the config, got %v", tokens)
+	}
+}
+
+// Test string delimiters from a config file
+func TestConfigStringDelimiters(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	config := `{"stringDelimiters": [
+		{"open": "\u00ab", "close": "\u00bb"},
+		{"open": "\u27e8", "close": "\u27e9", "tokenType": "ANGLE_STRING", "allowEscapes": true},
+		{"open": "[[", "close": "]]", "tokenType": "LONG_STRING"}
+	]}`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input    string
+		expected TokenType
+		literal  string
+		hasError bool
+	}{
+		{"\u00abhello\u00bb", STRING, "hello", false},
+		{"\u00aba\\nb\u00bb", STRING, "a\\nb", false},
+		{"\u27e8a\\nb\u27e9", "ANGLE_STRING", "a\nb", false},
+		{"\u27e8a\\\u27e9b\u27e9", "ANGLE_STRING", "a\u27e9b", false},
+		{"[[x = \"y\"]]", "LONG_STRING", "x = \"y\"", false},
+		{"\u00abhello\u27e9", STRING, "hello\u27e9", true},
+		{"\u00abhello\u00ab", STRING, "hello\u00ab", true},
+		{"\u00abhello", STRING, "hello", true},
+		{"\u00ab", STRING, "", true},
+	}
+
+	for _, tt := range tests {
+		l := NewLexerWithConfig(tt.input, configFile)
+		tok := l.NextToken()
+		if tok.Type != tt.expected || tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected %s %q, got %s %q", tt.input, tt.expected, tt.literal, tok.Type, tok.Literal)
+		}
+		if next := l.NextToken(); next.Type != EOF {
+			t.Errorf("Input %q: expected EOF next, got %s %q", tt.input, next.Type, next.Literal)
+		}
+		if l.HasErrors() != tt.hasError {
+			t.Errorf("Input %q: expected error=%v, got %v", tt.input, tt.hasError, l.GetErrors())
+		}
+		if tt.hasError && (l.GetErrors()[0].Line != 1 || l.GetErrors()[0].Column != 1) {
+			t.Errorf("Input %q: expected the error at the opening delimiter, got %v", tt.input, l.GetErrors()[0])
+		}
+	}
+
+	tokens, errors := NewLexerWithConfig("let x = \u00abhello\u00bb;", configFile).TokenizeAll()
+	expected := []Token{
+		NewToken(LET, "let", 1, 1),
+		NewToken(IDENT, "x", 1, 5),
+		NewToken(ASSIGN, "=", 1, 7),
+		NewToken(STRING, "hello", 1, 9),
+		NewToken(SEMICOLON, ";", 1, 16),
+	}
+	if len(errors) != 0 || !TokenSliceEqual(tokens, expected) {
+		t.Errorf("Expected %v, got %v with errors %v", expected, tokens, errors)
+	}
+	if tokens[4].Column != 16 {
+		t.Errorf("Expected ';' at column 16, got %d", tokens[4].Column)
+	}
+
+	// String delimiters belong to the lexer that loaded them
+	if _, errors := NewLexer("\u00abhello\u00bb").TokenizeAll(); len(errors) == 0 {
+		t.Errorf("Expected errors for \u00ab without the config")
+	}
+}
+
+// Test input size helpers
+func TestLineCountAndInputLength(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"", 0},
+		{"let x = 1;", 1},
+		{"let x = 1;\n", 1},
+		{strings.Repeat("x\n", 9) + "x", 10},
+		{"a\n\nb", 3},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		if l.LineCount() != tt.expected {
+			t.Errorf("Input %q: expected %d lines, got %d", tt.input, tt.expected, l.LineCount())
+		}
+		if l.InputLength() != len(tt.input) {
+			t.Errorf("Input %q: expected length %d, got %d", tt.input, len(tt.input), l.InputLength())
+		}
+	}
+
+	if l := NewLexer("é"); l.InputLength() != 2 {
+		t.Errorf("Expected byte length 2, got %d", l.InputLength())
+	}
+}
+
+// Test IsAtEOF reports when the input is exhausted
+func TestIsAtEOF(t *testing.T) {
+	l := NewLexer("let x = 1;")
+	if l.IsAtEOF() {
+		t.Errorf("Expected IsAtEOF to be false before lexing")
+	}
+	l.TokenizeAll()
+	if !l.IsAtEOF() {
+		t.Errorf("Expected IsAtEOF to be true after TokenizeAll")
+	}
+}
+
+// Test ternary, null coalescing and optional chaining operators
+func TestConditionalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []TokenType
+	}{
+		{`a ? b : c`, []TokenType{IDENT, QUESTION, IDENT, COLON, IDENT}},
+		{`a?b:c`, []TokenType{IDENT, QUESTION, IDENT, COLON, IDENT}},
+		{`a ?? b`, []TokenType{IDENT, NULL_COALESCE, IDENT}},
+		{`a?.b`, []TokenType{IDENT, OPT_CHAIN, IDENT}},
+		{`a ? . b`, []TokenType{IDENT, QUESTION, DOT, IDENT}},
+		{`a ??? b`, []TokenType{IDENT, NULL_COALESCE, QUESTION, IDENT}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %d", tt.input, len(tt.expected), len(tokens))
+			continue
+		}
+		for i, expectedType := range tt.expected {
+			if tokens[i].Type != expectedType {
+				t.Errorf("Input %q[%d]: expected %s, got %s", tt.input, i, expectedType, tokens[i].Type)
+			}
+		}
+	}
+}
+
+// Test XOR operators, including alongside a configured power operator
+func TestCaretOperators(t *testing.T) {
+	restorePackageTables(t)
+	config := &Config{AdditionalOperators: map[string]string{"**": "POWER"}}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no conflicts merging '**', got %v", err)
+	}
+	config.MergeWithDefaults()
+
+	tests := []struct {
+		input    string
+		expected []TokenType
+	}{
+		{"a ^ b", []TokenType{IDENT, CARET, IDENT}},
+		{"a ^= b", []TokenType{IDENT, XOR_ASSIGN, IDENT}},
+		{"a ** b ^ c", []TokenType{IDENT, "POWER", IDENT, CARET, IDENT}},
+		{"a *= b ^ 0xFF", []TokenType{IDENT, MULTIPLY_ASSIGN, IDENT, CARET, NUMBER}},
+		{"^^", []TokenType{CARET, CARET}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %d", tt.input, len(tt.expected), len(tokens))
+			continue
+		}
+		for i, expectedType := range tt.expected {
+			if tokens[i].Type != expectedType {
+				t.Errorf("Input %q[%d]: expected %s, got %s", tt.input, i, expectedType, tokens[i].Type)
+			}
+		}
+	}
+
+	if CARET.Precedence() != PLUS.Precedence() || XOR_ASSIGN.Precedence() != ASSIGN.Precedence() {
+		t.Errorf("Expected ^ to bind like + and ^= like =")
+	}
+	if !XOR_ASSIGN.IsRightAssociative() {
+		t.Errorf("Expected ^= to be right associative")
+	}
+}
+
+// Test '$' in identifiers with WithAllowDollarInIdentifiers
+func TestAllowDollarInIdentifiers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Token
+		errors   int
+	}{
+		{"$foo", []Token{{Type: IDENT, Literal: "$foo"}}, 0},
+		{"$$", []Token{{Type: IDENT, Literal: "$$"}}, 0},
+		{"a$b1", []Token{{Type: IDENT, Literal: "a$b1"}}, 0},
+		{"$42", []Token{{Type: ILLEGAL, Literal: "$"}, {Type: NUMBER, Literal: "42"}}, 1},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input, WithAllowDollarInIdentifiers(true)).TokenizeAll()
+		if len(errors) != tt.errors {
+			t.Errorf("Input %q: expected %d errors, got %v", tt.input, tt.errors, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %d", tt.input, len(tt.expected), len(tokens))
+			continue
+		}
+		for i, expected := range tt.expected {
+			if tokens[i].Type != expected.Type || tokens[i].Literal != expected.Literal {
+				t.Errorf("Input %q[%d]: expected %s %q, got %s %q", tt.input, i, expected.Type, expected.Literal, tokens[i].Type, tokens[i].Literal)
+			}
+		}
+	}
+
+	// Without the option '$' is not part of identifiers. The example config
+	// registers it as punctuation, so remove that while checking the default.
+	setSingleCharToken(t, '$', "")
+	tokens, errors := NewLexer("$foo").TokenizeAll()
+	if len(errors) == 0 {
+		t.Errorf("Expected an error for '$foo' without the option")
+	}
+	if len(tokens) != 2 || tokens[0].Type != ILLEGAL || tokens[1].Literal != "foo" {
+		t.Errorf("Expected ILLEGAL followed by 'foo', got %v", tokens)
+	}
+}
+
+// Test combining marks and letter numbers with WithUnicodeIdentifiers
+func TestUnicodeIdentifiers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Token
+		errors   int
+	}{
+		{"a\u0301", []Token{{Type: IDENT, Literal: "a\u0301"}}, 0},
+		{"cafe\u0301 = 1", []Token{{Type: IDENT, Literal: "cafe\u0301"}, {Type: ASSIGN, Literal: "="}, {Type: NUMBER, Literal: "1"}}, 0},
+		{"king\u216B", []Token{{Type: IDENT, Literal: "king\u216B"}}, 0},
+		{"\u0915\u093F", []Token{{Type: IDENT, Literal: "\u0915\u093F"}}, 0},
+		{"x\u0663", []Token{{Type: IDENT, Literal: "x\u0663"}}, 0},
+		{"\u0301a", []Token{{Type: ILLEGAL, Literal: "\u0301"}, {Type: IDENT, Literal: "a"}}, 1},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input, WithUnicodeIdentifiers(true)).TokenizeAll()
+		if len(errors) != tt.errors {
+			t.Errorf("Input %q: expected %d errors, got %v", tt.input, tt.errors, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %v", tt.input, len(tt.expected), tokens)
+			continue
+		}
+		for i, expected := range tt.expected {
+			if tokens[i].Type != expected.Type || tokens[i].Literal != expected.Literal {
+				t.Errorf("Input %q[%d]: expected %s %q, got %s %q", tt.input, i, expected.Type, expected.Literal, tokens[i].Type, tokens[i].Literal)
+			}
+		}
+	}
+
+	// Without the option a combining mark ends the identifier
+	tokens, errors := NewLexer("a\u0301").TokenizeAll()
+	if len(errors) != 1 || len(tokens) != 2 || tokens[0].Literal != "a" {
+		t.Errorf("Expected 'a' followed by an error, got %v %v", tokens, errors)
+	}
+}
+
+// Test that readIdentifier accepts every character that starts an
+// identifier in nextToken, so the ILLEGAL fallback never stalls the lexer
+func TestIdentifierStartAlwaysReads(t *testing.T) {
+	optionSets := [][]Option{
+		nil,
+		{WithAllowDollarInIdentifiers(true)},
+		{WithUnicodeIdentifiers(true)},
+	}
+
+	for _, opts := range optionSets {
+		for ch := rune(1); ch < 0x3000; ch++ {
+			l := NewLexer(string(ch)+"x", opts...)
+			if !l.isIdentStart(l.ch) {
+				continue
+			}
+			if literal := l.readIdentifier(); literal == "" {
+				t.Errorf("Rune %U: starts an identifier but readIdentifier returned nothing", ch)
+			}
+		}
+	}
+
+	for _, input := range []string{"_", "\u00e9", "$", "a\u0301", "\u0301", "\u216B\u216B"} {
+		l := NewLexer(input, WithAllowDollarInIdentifiers(true))
+		for i := 0; ; i++ {
+			if i > len(input) {
+				t.Fatalf("Input %q: lexer did not reach EOF", input)
+			}
+			if l.NextToken().Type == EOF {
+				break
+			}
+		}
+	}
+}
+
+// Test a NUL byte is an unexpected character rather than the end of input
+func TestNulByteIsNotEOF(t *testing.T) {
+	l := NewLexer("a\x00b")
+	expected := []Token{
+		NewToken(IDENT, "a", 1, 1),
+		NewToken(ILLEGAL, "\x00", 1, 2),
+		NewToken(IDENT, "b", 1, 3),
+		NewToken(EOF, "", 1, 4),
+	}
+	for _, exp := range expected {
+		tok := l.NextToken()
+		if tok.Type != exp.Type || tok.Literal != exp.Literal || tok.Column != exp.Column {
+			t.Errorf("Expected %s %q at column %d, got %s %q at column %d", exp.Type, exp.Literal, exp.Column, tok.Type, tok.Literal, tok.Column)
+		}
+	}
+	if errs := l.GetErrorsWithCode(CodeUnexpectedChar); len(errs) != 1 {
+		t.Errorf("Expected one unexpected character error, got %v", l.GetErrors())
+	}
+
+	tokens, errors := NewLexer("\"a\x00b\" `c\x00d` 'x' /* \x00 */ // \x00\n1").TokenizeAll()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+	if len(tokens) != 4 || tokens[0].Literal != "a\x00b" || tokens[1].Literal != "c\x00d" || tokens[3].Literal != "1" {
+		t.Errorf("Expected NUL to be kept inside literals and comments, got %v", tokens)
+	}
+
+	if NewLexer("\x00").IsAtEOF() {
+		t.Error("Expected a lexer positioned on NUL not to be at EOF")
+	}
+}
+
+// Test reading past the end of input keeps reporting the same position
+func TestRepeatedEOFPosition(t *testing.T) {
+	l := NewLexer("x\ny")
+	l.TokenizeAll()
+	for i := 0; i < 3; i++ {
+		tok := l.NextToken()
+		if tok.Type != EOF || tok.Line != 2 || tok.Column != 2 || tok.Offset != 3 {
+			t.Errorf("Call %d: expected EOF at 2:2 offset 3, got %s at %d:%d offset %d", i, tok.Type, tok.Line, tok.Column, tok.Offset)
+		}
+	}
+	if !l.IsAtEOF() {
+		t.Error("Expected IsAtEOF after the last token")
+	}
+}
+
+// setSingleCharToken overrides a single character token for the rest of
+// a test, undoing changes made by configs other tests have loaded. An
+// empty type removes the character.
+func setSingleCharToken(t *testing.T, ch rune, tokType TokenType) {
+	saved, existed := singleCharTokens[ch]
+	if tokType == "" {
+		delete(singleCharTokens, ch)
+	} else {
+		singleCharTokens[ch] = tokType
+	}
+	t.Cleanup(func() {
+		if existed {
+			singleCharTokens[ch] = saved
+		} else {
+			delete(singleCharTokens, ch)
+		}
+	})
+}
+
+// Test annotations and '@' on its own
+func TestAnnotations(t *testing.T) {
+	setSingleCharToken(t, '@', AT)
+
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected []Token
+	}{
+		{"@decorator", nil, []Token{{Type: ANNOTATION, Literal: "@decorator"}}},
+		{"@(x)", nil, []Token{{Type: AT, Literal: "@"}, {Type: LPAREN, Literal: "("}, {Type: IDENT, Literal: "x"}, {Type: RPAREN, Literal: ")"}}},
+		{"@3", nil, []Token{{Type: AT, Literal: "@"}, {Type: NUMBER, Literal: "3"}}},
+		{"a @ b", nil, []Token{{Type: IDENT, Literal: "a"}, {Type: AT, Literal: "@"}, {Type: IDENT, Literal: "b"}}},
+		{"@decorator", []Option{WithAnnotationTokens(false)}, []Token{{Type: AT, Literal: "@"}, {Type: IDENT, Literal: "decorator"}}},
+		{"@$x", []Option{WithAllowDollarInIdentifiers(true)}, []Token{{Type: ANNOTATION, Literal: "@$x"}}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input, tt.opts...).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %v", tt.input, len(tt.expected), tokens)
+			continue
+		}
+		for i, expected := range tt.expected {
+			if tokens[i].Type != expected.Type || tokens[i].Literal != expected.Literal {
+				t.Errorf("Input %q[%d]: expected %s %q, got %s %q", tt.input, i, expected.Type, expected.Literal, tokens[i].Type, tokens[i].Literal)
+			}
+		}
+	}
+}
+
+// Test newline tokens emitted with WithEmitNewlines
+func TestEmitNewlines(t *testing.T) {
+	tests := []struct {
+		input    string
+		enabled  bool
+		expected []TokenType
+	}{
+		{"a\nb", true, []TokenType{IDENT, NEWLINE, IDENT}},
+		{"a\nb", false, []TokenType{IDENT, IDENT}},
+		{"a  \t\n\n\nb", true, []TokenType{IDENT, NEWLINE, NEWLINE, NEWLINE, IDENT}},
+		{"a\r\nb\r\n", true, []TokenType{IDENT, NEWLINE, IDENT, NEWLINE}},
+		{"a // note\nb", true, []TokenType{IDENT, NEWLINE, IDENT}},
+		{"  a b  ", true, []TokenType{IDENT, IDENT}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input, WithEmitNewlines(tt.enabled)).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %v", tt.input, len(tt.expected), tokens)
+			continue
+		}
+		for i, expectedType := range tt.expected {
+			if tokens[i].Type != expectedType {
+				t.Errorf("Input %q[%d]: expected %s, got %s", tt.input, i, expectedType, tokens[i].Type)
+			}
+		}
+	}
+
+	// Newlines are reported on the line they end
+	tokens, _ := NewLexer("ab \r\n\ncd", WithEmitNewlines(true)).TokenizeAll()
+	expected := []struct {
+		literal      string
+		line, column int
+	}{
+		{"ab", 1, 1},
+		{"\r\n", 1, 4},
+		{"\n", 2, 1},
+		{"cd", 3, 1},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %v", len(expected), tokens)
+	}
+	for i, want := range expected {
+		tok := tokens[i]
+		if tok.Literal != want.literal || tok.Line != want.line || tok.Column != want.column {
+			t.Errorf("Token %d: expected %q at %d:%d, got %q at %d:%d", i, want.literal, want.line, want.column, tok.Literal, tok.Line, tok.Column)
+		}
+	}
+}
+
+// Test lexing bytes gives the same tokens as lexing a string
+func TestNewLexerFromBytes(t *testing.T) {
+	inputs := []string{
+		"let x = 42;",
+		"fn add(a, b) { return a + b; } // sum",
+		"\"héllo ${name}\" 'c' 0xFF 1e10",
+		"x § y",
+		"",
+	}
+
+	for _, input := range inputs {
+		expected, expectedErrs := NewLexer(input).TokenizeAll()
+		tokens, errs := NewLexerFromBytes([]byte(input)).TokenizeAll()
+
+		if !TokenSliceEqual(tokens, expected) {
+			t.Errorf("Input %q: expected %v, got %v", input, expected, tokens)
+		}
+		if len(errs) != len(expectedErrs) {
+			t.Errorf("Input %q: expected %d errors, got %d", input, len(expectedErrs), len(errs))
+		}
+	}
+
+	if tokens, _ := NewLexerFromBytes(nil).TokenizeAll(); len(tokens) != 0 {
+		t.Errorf("Expected no tokens for nil input, got %v", tokens)
+	}
+}
+
+func BenchmarkNewLexerFromString(b *testing.B) {
+	data := []byte(strings.Repeat("let x = 10 + y * 3.14; // comment\n", 200))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewLexer(string(data)).TokenizeAll()
+	}
+}
+
+func BenchmarkNewLexerFromBytes(b *testing.B) {
+	data := []byte(strings.Repeat("let x = 10 + y * 3.14; // comment\n", 200))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewLexerFromBytes(data).TokenizeAll()
+	}
+}
+
+// Test columns count runes rather than bytes
+func TestColumnsCountRunes(t *testing.T) {
+	tests := []struct {
+		input  string
+		column int // column of the last token
+		offset int // byte offset of the last token
+	}{
+		{"привет x", 8, 13},
+		{"变量 = 1", 6, 9},
+		{"\"😀😀\" y", 6, 11},
+		{"é\n  変数", 3, 5},
+	}
+
+	for _, tt := range tests {
+		tokens, _ := NewLexer(tt.input).TokenizeAll()
+		last := tokens[len(tokens)-1]
+		if last.Column != tt.column || last.Offset != tt.offset {
+			t.Errorf("Input %q: expected column %d at offset %d, got column %d at offset %d", tt.input, tt.column, tt.offset, last.Column, last.Offset)
+		}
+	}
+
+	tokens, _ := NewLexer("привет").TokenizeAll()
+	if len(tokens) != 1 || tokens[0].Type != IDENT || tokens[0].Column != 1 {
+		t.Errorf("Expected a single IDENT at column 1, got %v", tokens)
+	}
+}
+
+// Test operators written without spaces do not swallow the next token
+func TestOperatorsWithoutSpaces(t *testing.T) {
+	for _, op := range operators {
+		for _, form := range op.forms() {
+			if form.literal == "" || form.tokType == "" {
+				continue
+			}
+
+			input := "a" + form.literal + "b"
+			tokens, errors := NewLexer(input).TokenizeAll()
+			if len(errors) != 0 {
+				t.Errorf("Input %q: unexpected errors %v", input, errors)
+			}
+
+			expected := []Token{
+				NewToken(IDENT, "a", 1, 1),
+				NewToken(form.tokType, form.literal, 1, 2),
+				NewToken(IDENT, "b", 1, 2+len(form.literal)),
+			}
+			if !TokenSliceEqual(tokens, expected) {
+				t.Errorf("Input %q: expected %v, got %v", input, expected, tokens)
+				continue
+			}
+			if tokens[2].Column != expected[2].Column {
+				t.Errorf("Input %q: expected 'b' at column %d, got %d", input, expected[2].Column, tokens[2].Column)
+			}
+		}
+	}
+
+	tokens, _ := NewLexer("a==b").TokenizeAll()
+	if len(tokens) != 3 || tokens[1].Type != EQL || tokens[2].Literal != "b" {
+		t.Errorf("Expected IDENT EQL IDENT for a==b, got %v", tokens)
+	}
+}
+
+// Test columns on the line after a comment
+func TestColumnAfterComment(t *testing.T) {
+	tests := []struct {
+		input        string
+		line, column int // position of the last token
+	}{
+		{"// c\nfoo", 2, 1},
+		{"//\nz", 2, 1},
+		{"// c\r\nfoo", 2, 1},
+		{"x // c\n  y", 2, 3},
+		{"x //c\n\nw", 3, 1},
+		{"/* a\nb */ c", 2, 6},
+		{"foo\nbar", 2, 1}, // no comment
+	}
+
+	for _, tt := range tests {
+		tokens, _ := NewLexer(tt.input).TokenizeAll()
+		last := tokens[len(tokens)-1]
+		if last.Line != tt.line || last.Column != tt.column {
+			t.Errorf("Input %q: expected %d:%d, got %d:%d", tt.input, tt.line, tt.column, last.Line, last.Column)
+		}
+	}
+
+	tokens, _ := NewLexer("// c\nfoo", WithEmitComments(true)).TokenizeAll()
+	if len(tokens) != 2 || tokens[1].Column != 1 {
+		t.Errorf("Expected 'foo' at column 1 after an emitted comment, got %v", tokens)
+	}
+}
+
+// Test the character after a char literal is not skipped
+func TestCharLiteralFollowedByToken(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Token
+	}{
+		{"'a'b", []Token{NewToken(CHAR, "a", 1, 1), NewToken(IDENT, "b", 1, 4)}},
+		{"'\\n'x", []Token{NewToken(CHAR, "\n", 1, 1), NewToken(IDENT, "x", 1, 5)}},
+		{"'a''b'", []Token{NewToken(CHAR, "a", 1, 1), NewToken(CHAR, "b", 1, 4)}},
+		{"f('a');", []Token{
+			NewToken(IDENT, "f", 1, 1),
+			NewToken(LPAREN, "(", 1, 2),
+			NewToken(CHAR, "a", 1, 3),
+			NewToken(RPAREN, ")", 1, 6),
+			NewToken(SEMICOLON, ";", 1, 7),
+		}},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) != 0 {
+			t.Errorf("Input %q: unexpected errors %v", tt.input, errors)
+		}
+		if !TokenSliceEqual(tokens, tt.expected) {
+			t.Errorf("Input %q: expected %v, got %v", tt.input, tt.expected, tokens)
+			continue
+		}
+		for i := range tokens {
+			if tokens[i].Column != tt.expected[i].Column {
+				t.Errorf("Input %q[%d]: expected column %d, got %d", tt.input, i, tt.expected[i].Column, tokens[i].Column)
+			}
+		}
+	}
+}
+
+// Test octal escape sequences in strings and char literals
+func TestOctalEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		tokType  TokenType
+		expected string
+		errors   int
+	}{
+		{`"\077"`, STRING, "?", 0},
+		{`'\010'`, CHAR, "\b", 0},
+		{`"\0"`, STRING, "\x00", 0},
+		{`"\7"`, STRING, "\a", 0},
+		{`"\0771"`, STRING, "?1", 0}, // at most three digits
+		{`"\08"`, STRING, "\x008", 1},
+		{`"\19"`, STRING, "\x019", 1},
+		{`"\377"`, STRING, "\u00ff", 0},
+		{`"\400"`, STRING, "", 1},
+		{`'\777'`, CHAR, "", 1},
+	}
+
+	for _, tt := range tests {
+		tokens, errors := NewLexer(tt.input).TokenizeAll()
+		if len(errors) != tt.errors {
+			t.Errorf("Input %s: expected %d errors, got %v", tt.input, tt.errors, errors)
+		}
+		if tt.errors > 0 && len(errors) > 0 && !errors.HasCode(CodeInvalidEscape) {
+			t.Errorf("Input %s: expected an invalid escape error, got %v", tt.input, errors)
+		}
+		if len(tokens) != 1 || tokens[0].Type != tt.tokType || tokens[0].Literal != tt.expected {
+			t.Errorf("Input %s: expected %s %q, got %v", tt.input, tt.tokType, tt.expected, tokens)
+		}
+	}
+}
+
+// Test unterminated backtick strings report the opening backtick
+func TestUnterminatedBacktickString(t *testing.T) {
+	tests := []struct {
+		input        string
+		line, column int
+	}{
+		{"`", 1, 1},
+		{"x = `abc", 1, 5},
+		{"let s =\n  `line one\nline two\n\nline four", 2, 3},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		l.TokenizeAll()
+		if !l.HasErrors() {
+			t.Errorf("Input %q: expected an error", tt.input)
+			continue
+		}
+
+		err := l.GetErrors()[0]
+		if err.Message != "unterminated backtick string literal" || err.Code != CodeUnterminatedString {
+			t.Errorf("Input %q: unexpected error %v", tt.input, err)
+		}
+		if err.Line != tt.line || err.Column != tt.column {
+			t.Errorf("Input %q: expected error at %d:%d, got %d:%d", tt.input, tt.line, tt.column, err.Line, err.Column)
+		}
+	}
+}
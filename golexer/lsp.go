@@ -0,0 +1,273 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Language Server Protocol Helpers
+Converts lexer output into the JSON shapes expected by editors that
+speak the Language Server Protocol. Lexer positions are 1-indexed
+while LSP positions are 0-indexed, so every conversion subtracts one
+from both line and column.
+
+Note: LSP characters are UTF-16 code units, while the lexer counts
+columns in runes. The Lexer methods convert using the lexer's input, so
+characters outside the BMP (emoji and rare CJK characters) count as two
+units. The package functions have no source text and pass rune columns
+through unchanged.
+*/
+
+package golexer
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// LSP diagnostic severities
+const (
+	lspSeverityError   = 1
+	lspSeverityWarning = 2
+)
+
+// lspSeverity maps an error severity to an LSP DiagnosticSeverity
+func lspSeverity(s ErrorSeverity) int {
+	if s == SeverityWarning {
+		return lspSeverityWarning
+	}
+	return lspSeverityError
+}
+
+// lspPosition builds an LSP Position from 1-indexed line and column values
+func lspPosition(line, column int) map[string]interface{} {
+	if line < 1 {
+		line = 1
+	}
+	if column < 1 {
+		column = 1
+	}
+	return map[string]interface{}{
+		"line":      line - 1,
+		"character": column - 1,
+	}
+}
+
+// lspPosition builds an LSP Position from a 1-indexed line and rune
+// column of the lexer's input, counting the character in UTF-16 units
+func (l *Lexer) lspPosition(line, column int) map[string]interface{} {
+	return lspPosition(line, l.utf16Column(line, column))
+}
+
+// utf16Column converts a 1-indexed rune column into a 1-indexed UTF-16
+// column. Columns past the end of the line count one unit per rune.
+func (l *Lexer) utf16Column(line, column int) int {
+	text, err := l.lineText(line)
+	if err != nil {
+		return column
+	}
+	units := 1
+	for _, r := range text {
+		if column <= 1 {
+			return units
+		}
+		units += utf16Len(r)
+		column--
+	}
+	return units + column - 1
+}
+
+// utf16Len returns the number of UTF-16 code units a rune encodes to, as
+// utf16.RuneLen does from Go 1.23 but counting invalid runes as the one
+// unit of their replacement character
+func utf16Len(r rune) int {
+	if r > 0xFFFF && r <= utf8.MaxRune {
+		return 2
+	}
+	return 1
+}
+
+// lexErrorToDiagnostic builds a Diagnostic using position to convert
+// 1-indexed lines and columns
+func lexErrorToDiagnostic(e *LexError, position func(line, column int) map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"range": map[string]interface{}{
+			"start": position(e.Line, e.Column),
+			"end":   position(e.Line, e.Column+1),
+		},
+		"severity": lspSeverity(e.Severity),
+		"source":   "golexer",
+		"message":  e.Message,
+	}
+}
+
+// LexErrorToDiagnostic converts a LexError into an LSP Diagnostic object.
+// The range covers the single character the error was reported at. Without
+// the source text, characters are rune columns; use the Lexer method of
+// the same name for UTF-16 characters.
+func LexErrorToDiagnostic(e *LexError) map[string]interface{} {
+	return lexErrorToDiagnostic(e, lspPosition)
+}
+
+// LexErrorsToDiagnostics converts a slice of LexErrors into LSP Diagnostics
+// like LexErrorToDiagnostic
+func LexErrorsToDiagnostics(errs []*LexError) []map[string]interface{} {
+	diagnostics := make([]map[string]interface{}, 0, len(errs))
+	for _, e := range errs {
+		diagnostics = append(diagnostics, LexErrorToDiagnostic(e))
+	}
+	return diagnostics
+}
+
+// LexErrorToDiagnostic converts an error from this lexer's input into an
+// LSP Diagnostic, with characters counted in UTF-16 code units
+func (l *Lexer) LexErrorToDiagnostic(e *LexError) map[string]interface{} {
+	return lexErrorToDiagnostic(e, l.lspPosition)
+}
+
+// LexErrorsToDiagnostics converts errors from this lexer's input into LSP
+// Diagnostics, with characters counted in UTF-16 code units
+func (l *Lexer) LexErrorsToDiagnostics(errs []*LexError) []map[string]interface{} {
+	diagnostics := make([]map[string]interface{}, 0, len(errs))
+	for _, e := range errs {
+		diagnostics = append(diagnostics, l.LexErrorToDiagnostic(e))
+	}
+	return diagnostics
+}
+
+// semanticLegend lists the token types reported to LSP clients, in index order
+var semanticLegend = []string{"keyword", "identifier", "number", "string", "operator", "comment"}
+
+// Semantic token type indices into the legend
+const (
+	semanticKeyword = iota
+	semanticIdentifier
+	semanticNumber
+	semanticString
+	semanticOperator
+	semanticComment
+)
+
+// semanticTokenType returns the legend index for a token, or false if
+// the token should not be highlighted
+func semanticTokenType(tok Token, isKeyword, isOperator func(Token) bool) (int, bool) {
+	switch {
+	case tok.IsComment():
+		return semanticComment, true
+	case isKeyword(tok), tok.IsType():
+		return semanticKeyword, true
+	case tok.IsIdentifier():
+		return semanticIdentifier, true
+	case tok.IsNumber():
+		return semanticNumber, true
+	case tok.IsLiteral():
+		return semanticString, true
+	case isOperator(tok):
+		return semanticOperator, true
+	}
+	return 0, false
+}
+
+// semanticTokenLength returns the length of a token's source text in
+// UTF-16 code units without access to the source. The byte span from
+// Offset to EndOffset is reduced by the extra bytes each character of the
+// literal takes in UTF-8, which is exact unless escapes in the source
+// produce characters outside ASCII. Tokens without an EndOffset fall back
+// to the literal plus its quotes.
+func semanticTokenLength(tok Token) int {
+	if tok.EndOffset > tok.Offset {
+		length := tok.EndOffset - tok.Offset
+		for _, r := range tok.Literal {
+			length -= utf8.RuneLen(r) - utf16Len(r)
+		}
+		return length
+	}
+
+	length := len(utf16.Encode([]rune(tok.Literal)))
+	switch tok.Type {
+	case STRING, CHAR, BACKTICK_STRING:
+		length += 2
+	case RAW_STRING, BYTE_STRING:
+		length += 3
+	case TRIPLE_STRING, TRIPLE_BACKTICK:
+		length += 6
+	case FSTRING_MIDDLE:
+		// Braces in f-string text are written doubled
+		length += strings.Count(tok.Literal, "{") + strings.Count(tok.Literal, "}")
+	}
+	return length
+}
+
+// SemanticTokenTypes returns the semantic token legend. The tokenType
+// values produced by SemanticTokens are indices into this slice.
+func SemanticTokenTypes() []string {
+	legend := make([]string, len(semanticLegend))
+	copy(legend, semanticLegend)
+	return legend
+}
+
+// SemanticTokens encodes tokens in the LSP semantic tokens format: five
+// integers per token holding deltaLine, deltaStartChar, length, tokenType
+// and tokenModifiers. EOF and punctuation tokens are left out. Without the
+// source text, start characters are rune columns; use the Lexer method of
+// the same name for UTF-16 characters.
+func SemanticTokens(tokens []Token) []uint32 {
+	return semanticTokens(tokens, Token.IsKeyword, Token.IsOperator, func(tok Token) int { return tok.Column }, semanticTokenLength)
+}
+
+// SemanticTokens encodes tokens from this lexer's input like the package
+// function, with start characters and lengths in UTF-16 code units.
+// Keywords and operators from the lexer's config are highlighted as such.
+func (l *Lexer) SemanticTokens(tokens []Token) []uint32 {
+	column := func(tok Token) int { return l.utf16Column(tok.Line, tok.Column) }
+	length := func(tok Token) int {
+		if tok.Offset < 0 || tok.EndOffset <= tok.Offset || tok.EndOffset > len(l.input) {
+			return semanticTokenLength(tok)
+		}
+		units := 0
+		for _, r := range l.input[tok.Offset:tok.EndOffset] {
+			units += utf16Len(r)
+		}
+		return units
+	}
+	return semanticTokens(tokens, l.IsKeyword, l.IsOperator, column, length)
+}
+
+// semanticTokens encodes tokens using isKeyword and isOperator to
+// classify keywords and operators, column for the 1-indexed start
+// character and length for the length of each token
+func semanticTokens(tokens []Token, isKeyword, isOperator func(Token) bool, column, length func(Token) int) []uint32 {
+	var data []uint32
+	prevLine, prevChar := 0, 0
+
+	for _, tok := range tokens {
+		tokType, ok := semanticTokenType(tok, isKeyword, isOperator)
+		if !ok {
+			continue
+		}
+
+		line := tok.Line - 1
+		char := column(tok) - 1
+		deltaLine := line - prevLine
+		deltaChar := char
+		if deltaLine == 0 {
+			deltaChar = char - prevChar
+		}
+
+		data = append(data,
+			uint32(deltaLine),
+			uint32(deltaChar),
+			uint32(length(tok)),
+			uint32(tokType),
+			0,
+		)
+		prevLine, prevChar = line, char
+	}
+
+	return data
+}
+
+// SemanticTokensFromStream is equivalent to SemanticTokens
+func SemanticTokensFromStream(tokens []Token) []uint32 {
+	return SemanticTokens(tokens)
+}
@@ -0,0 +1,177 @@
+package golexer
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test converting a LexError into an LSP Diagnostic
+func TestLexErrorToDiagnostic(t *testing.T) {
+	err := &LexError{Message: "unterminated string literal", Line: 3, Column: 7}
+
+	expected := map[string]interface{}{
+		"range": map[string]interface{}{
+			"start": map[string]interface{}{"line": 2, "character": 6},
+			"end":   map[string]interface{}{"line": 2, "character": 7},
+		},
+		"severity": 1,
+		"source":   "golexer",
+		"message":  "unterminated string literal",
+	}
+
+	got := LexErrorToDiagnostic(err)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+// Test converting lexer errors into diagnostics
+func TestLexErrorsToDiagnostics(t *testing.T) {
+	_, errors := NewLexer("let x = &;\n0xZZ").TokenizeAll()
+	diagnostics := LexErrorsToDiagnostics(errors)
+
+	if len(diagnostics) != len(errors) {
+		t.Fatalf("Expected %d diagnostics, got %d", len(errors), len(diagnostics))
+	}
+	for i, d := range diagnostics {
+		if d["message"] != errors[i].Message {
+			t.Errorf("Diagnostic %d: expected message %q, got %q", i, errors[i].Message, d["message"])
+		}
+	}
+}
+
+// Test semantic token delta encoding
+func TestSemanticTokensFromStream(t *testing.T) {
+	tokens, _ := NewLexer("let x = 42;\n  \"hi\"").TokenizeAll()
+	data := SemanticTokensFromStream(tokens)
+
+	expected := []uint32{
+		0, 0, 3, semanticKeyword, 0, // let
+		0, 4, 1, semanticIdentifier, 0, // x
+		0, 2, 1, semanticOperator, 0, // =
+		0, 2, 2, semanticNumber, 0, // 42
+		1, 2, 4, semanticString, 0, // "hi" on the next line
+	}
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+// Test the semantic token legend order
+func TestSemanticTokenTypes(t *testing.T) {
+	expected := []string{"keyword", "identifier", "number", "string", "operator", "comment"}
+	if !reflect.DeepEqual(SemanticTokenTypes(), expected) {
+		t.Errorf("Expected legend %v, got %v", expected, SemanticTokenTypes())
+	}
+}
+
+// Test semantic token encoding of a hand-built stream
+func TestSemanticTokens(t *testing.T) {
+	tokens := []Token{
+		{Type: FN, Literal: "fn", Line: 1, Column: 1},
+		{Type: IDENT, Literal: "add", Line: 1, Column: 4},
+		{Type: RETURN, Literal: "return", Line: 2, Column: 5},
+		{Type: NUMBER, Literal: "10", Line: 2, Column: 12},
+		{Type: PLUS, Literal: "+", Line: 2, Column: 15},
+		{Type: EOF, Literal: "", Line: 2, Column: 16},
+	}
+
+	expected := []uint32{
+		0, 0, 2, 0, 0, // fn: line 0, char 0
+		0, 3, 3, 1, 0, // add: same line, 4-1 = 3
+		1, 4, 6, 0, 0, // return: new line, absolute char 4
+		0, 7, 2, 2, 0, // 10: 11-4 = 7
+		0, 3, 1, 4, 0, // +: 14-11 = 3
+	}
+
+	data := SemanticTokens(tokens)
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+	if len(data)%5 != 0 || len(data)/5 != len(tokens)-1 {
+		t.Errorf("Expected EOF to be excluded, got %d entries", len(data)/5)
+	}
+}
+
+// Test that emitted comments are reported as comment semantic tokens
+func TestSemanticTokensComments(t *testing.T) {
+	tokens, _ := NewLexer("x // note", WithEmitComments(true)).TokenizeAll()
+	expected := []uint32{
+		0, 0, 1, semanticIdentifier, 0,
+		0, 2, 7, semanticComment, 0,
+	}
+	if data := SemanticTokens(tokens); !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+// Test that warnings map to the LSP warning severity
+func TestLexErrorToDiagnosticSeverity(t *testing.T) {
+	warning := &LexError{Message: "style", Line: 1, Column: 1, Severity: SeverityWarning}
+	if got := LexErrorToDiagnostic(warning)["severity"]; got != 2 {
+		t.Errorf("Expected severity 2 for a warning, got %v", got)
+	}
+}
+
+// Test that lengths come from the source text rather than the literal
+func TestSemanticTokenLengthFromSource(t *testing.T) {
+	tests := []struct {
+		input  string
+		length uint32
+	}{
+		{`"a\tb"`, 6},
+		{`r"a\b"`, 6},
+		{`"é"`, 3},
+		{`"😀"`, 4},
+		{"`x`", 3},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		tokens, _ := l.TokenizeAll()
+		if data := SemanticTokens(tokens); len(data) != 5 || data[2] != tt.length {
+			t.Errorf("Input %q: expected length %d, got %v", tt.input, tt.length, data)
+		}
+		if data := l.SemanticTokens(tokens); len(data) != 5 || data[2] != tt.length {
+			t.Errorf("Input %q: expected length %d from the lexer, got %v", tt.input, tt.length, data)
+		}
+	}
+}
+
+// Test that the Lexer methods count characters in UTF-16 code units
+func TestLSPUTF16Positions(t *testing.T) {
+	input := "s = \"😀\" + x §"
+	l := NewLexer(input)
+	tokens, errs := l.TokenizeAll()
+
+	expected := []uint32{
+		0, 0, 1, semanticIdentifier, 0, // s
+		0, 2, 1, semanticOperator, 0, // =
+		0, 2, 4, semanticString, 0, // "😀" is four UTF-16 units
+		0, 5, 1, semanticOperator, 0, // + starts at character 9, not 8
+		0, 2, 1, semanticIdentifier, 0, // x
+	}
+	if data := l.SemanticTokens(tokens); !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected one error, got %v", errs)
+	}
+	diagnostic := l.LexErrorToDiagnostic(errs[0])["range"].(map[string]interface{})
+	start := diagnostic["start"].(map[string]interface{})
+	end := diagnostic["end"].(map[string]interface{})
+	if start["character"] != 13 || end["character"] != 14 {
+		t.Errorf("Expected the error at UTF-16 characters 13-14, got %v", diagnostic)
+	}
+	if got := l.LexErrorsToDiagnostics(errs); len(got) != 1 || !reflect.DeepEqual(got[0]["range"], diagnostic) {
+		t.Errorf("Expected LexErrorsToDiagnostics to match, got %v", got)
+	}
+
+	// Without the source, characters stay rune columns
+	start = LexErrorToDiagnostic(errs[0])["range"].(map[string]interface{})["start"].(map[string]interface{})
+	if start["character"] != 12 {
+		t.Errorf("Expected rune character 12 from the package function, got %v", start["character"])
+	}
+}
@@ -0,0 +1,61 @@
+package golexer
+
+import (
+	"os"
+	"testing"
+)
+
+// Shared fixtures set up once by TestMain. The keyword and operator tables
+// are copied before any test runs, so tests that list the built-in tokens
+// do not see tokens a config merged into the package tables.
+var (
+	defaultKeywords  map[string]TokenType
+	defaultOperators []Operator
+	testCorpus       string
+)
+
+func TestMain(m *testing.M) {
+	defaultKeywords = make(map[string]TokenType, len(keywords))
+	for word, tokType := range keywords {
+		defaultKeywords[word] = tokType
+	}
+	defaultOperators = append([]Operator(nil), operators...)
+	testCorpus = generateSource(64 * 1024)
+
+	os.Exit(m.Run())
+}
+
+// restorePackageTables saves the package keyword, operator and
+// punctuation tables and restores them when the test ends. Tests that
+// merge a config into the package tables call it first, so the tokens
+// they add do not leak into tests that run later.
+func restorePackageTables(t *testing.T) {
+	savedKeywords := make(map[string]TokenType, len(keywords))
+	for word, tokType := range keywords {
+		savedKeywords[word] = tokType
+	}
+	savedKeywordTypes := make(map[TokenType]struct{}, len(keywordTypes))
+	for tokType := range keywordTypes {
+		savedKeywordTypes[tokType] = struct{}{}
+	}
+	savedOperators := append([]Operator(nil), operators...)
+	savedOperatorOrder := operatorOrder
+	savedOperatorTypes := make(map[TokenType]struct{}, len(operatorTypes))
+	for tokType := range operatorTypes {
+		savedOperatorTypes[tokType] = struct{}{}
+	}
+	savedSingleChars := make(map[rune]TokenType, len(singleCharTokens))
+	for ch, tokType := range singleCharTokens {
+		savedSingleChars[ch] = tokType
+	}
+
+	t.Cleanup(func() {
+		keywords = savedKeywords
+		keywordTypes = savedKeywordTypes
+		rebuildKeywordIndex()
+		operators = savedOperators
+		operatorOrder = savedOperatorOrder
+		operatorTypes = savedOperatorTypes
+		singleCharTokens = savedSingleChars
+	})
+}
@@ -0,0 +1,223 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Lexer Middleware
+Composable transformations over the token stream. Each middleware
+receives the function producing the upstream tokens and returns a new
+function, so filtering, injection and logging can be layered on top of
+a lexer without changing it.
+*/
+
+package golexer
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// LexerMiddleware transforms a token source into a new token source
+type LexerMiddleware func(next func() Token) func() Token
+
+// WrappedLexer is a Lexer with middleware applied to its tokens
+type WrappedLexer struct {
+	lexer *Lexer
+	next  func() Token
+}
+
+// WrapLexer applies middleware to a lexer. The first middleware sees the
+// lexer's tokens directly and each later one sees the output of the one
+// before it.
+func WrapLexer(l *Lexer, mw ...LexerMiddleware) *WrappedLexer {
+	next := l.NextToken
+	for _, m := range mw {
+		next = m(next)
+	}
+	return &WrappedLexer{lexer: l, next: next}
+}
+
+// NextToken returns the next transformed token
+func (w *WrappedLexer) NextToken() Token {
+	return w.next()
+}
+
+// TokenizeAll returns all transformed tokens along with the lexer's errors
+func (w *WrappedLexer) TokenizeAll() ([]Token, LexErrorSlice) {
+	var tokens []Token
+
+	for {
+		tok := w.NextToken()
+		if tok.Type == EOF {
+			break
+		}
+		tokens = append(tokens, tok)
+	}
+
+	return tokens, w.lexer.errors
+}
+
+// filterTokens returns middleware that drops tokens for which skip is true
+func filterTokens(skip func(Token) bool) LexerMiddleware {
+	return func(next func() Token) func() Token {
+		return func() Token {
+			for {
+				tok := next()
+				if tok.Type == EOF || !skip(tok) {
+					return tok
+				}
+			}
+		}
+	}
+}
+
+// SkipComments drops comment tokens
+func SkipComments() LexerMiddleware {
+	return filterTokens(Token.IsComment)
+}
+
+// SkipWhitespace drops WHITESPACE tokens
+func SkipWhitespace() LexerMiddleware {
+	return filterTokens(func(tok Token) bool { return tok.Type == WHITESPACE })
+}
+
+// semicolonTriggers contains the token types that end a statement when
+// they are the last token on a line
+var semicolonTriggers = map[TokenType]struct{}{
+	IDENT:           {},
+	NUMBER:          {},
+	FLOAT:           {},
+	INT:             {},
+	STRING:          {},
+	CHAR:            {},
+	BACKTICK_STRING: {},
+	TRIPLE_BACKTICK: {},
+	RAW_STRING:      {},
+	BYTE_STRING:     {},
+	FSTRING_END:     {},
+	TRUE:            {},
+	FALSE:           {},
+	NULL:            {},
+	RETURN:          {},
+	BREAK:           {},
+	CONTINUE:        {},
+	RPAREN:          {},
+	RBRACKET:        {},
+	RBRACE:          {},
+	INCREMENT:       {},
+	DECREMENT:       {},
+}
+
+// InjectSemicolons inserts a SEMICOLON after the last token on a line, or
+// before EOF, when that token can end a statement. Run it after
+// SkipComments if comments are emitted, since a trailing comment would
+// otherwise hide the end of the line. Without the source it takes a token
+// to end on the line it starts on; use Lexer.InjectSemicolons for input
+// with multi-line strings.
+func InjectSemicolons() LexerMiddleware {
+	return injectSemicolons(func(tok Token) (int, int) {
+		return tok.Line, tok.Column + len(tok.Literal)
+	})
+}
+
+// InjectSemicolons is like the package InjectSemicolons, but finds the line
+// a token ends on from its EndOffset, so a multi-line string followed by
+// more code on its closing line does not end the statement.
+func (l *Lexer) InjectSemicolons() LexerMiddleware {
+	return injectSemicolons(func(tok Token) (int, int) {
+		if tok.EndOffset <= tok.Offset || tok.EndOffset > len(l.input) {
+			return tok.Line, tok.Column + len(tok.Literal)
+		}
+		return l.offsetPosition(tok.EndOffset)
+	})
+}
+
+// injectSemicolons implements InjectSemicolons given a function returning
+// the line and column just past the end of a token
+func injectSemicolons(end func(Token) (int, int)) LexerMiddleware {
+	return func(next func() Token) func() Token {
+		var pending []Token
+		var prev Token
+
+		return func() Token {
+			if len(pending) > 0 {
+				tok := pending[0]
+				pending = pending[1:]
+				prev = tok
+				return tok
+			}
+
+			tok := next()
+			if _, ok := semicolonTriggers[prev.Type]; !ok {
+				prev = tok
+				return tok
+			}
+			if line, column := end(prev); tok.Type == EOF || tok.Line > line {
+				semi := Token{
+					Type:      SEMICOLON,
+					Literal:   ";",
+					Line:      line,
+					Column:    column,
+					Offset:    prev.EndOffset,
+					EndOffset: prev.EndOffset,
+				}
+				pending = append(pending, tok)
+				prev = semi
+				return semi
+			}
+
+			prev = tok
+			return tok
+		}
+	}
+}
+
+// NewSemicolonInserter wraps a lexer so that statements may end at a line
+// break instead of an explicit semicolon, following Go's insertion rule.
+// Comments are dropped so they never hide the end of a line.
+func NewSemicolonInserter(l *Lexer) *WrappedLexer {
+	return WrapLexer(l, SkipComments(), l.InjectSemicolons())
+}
+
+// MeteringMiddleware atomically adds one to *counter for every token it
+// passes on, not counting EOF
+func MeteringMiddleware(counter *int64) LexerMiddleware {
+	return func(next func() Token) func() Token {
+		return func() Token {
+			tok := next()
+			if tok.Type != EOF {
+				atomic.AddInt64(counter, 1)
+			}
+			return tok
+		}
+	}
+}
+
+// TokenRateMiddleware throttles the stream to at most maxPerSec tokens per
+// second by sleeping before a token that arrives too early. The first
+// token and EOF are never delayed. A rate of zero or less is unlimited.
+func TokenRateMiddleware(maxPerSec float64) LexerMiddleware {
+	return func(next func() Token) func() Token {
+		if maxPerSec <= 0 {
+			return next
+		}
+		interval := time.Duration(float64(time.Second) / maxPerSec)
+		var due time.Time
+
+		return func() Token {
+			tok := next()
+			if tok.Type == EOF {
+				return tok
+			}
+
+			now := time.Now()
+			if now.Before(due) {
+				time.Sleep(due.Sub(now))
+				now = due
+			}
+			due = now.Add(interval)
+			return tok
+		}
+	}
+}
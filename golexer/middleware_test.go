@@ -0,0 +1,192 @@
+package golexer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that middleware is applied in order
+func TestWrapLexerOrder(t *testing.T) {
+	var seen []TokenType
+	record := func(next func() Token) func() Token {
+		return func() Token {
+			tok := next()
+			seen = append(seen, tok.Type)
+			return tok
+		}
+	}
+
+	l := NewLexer("x // note\n  y", WithEmitComments(true), WithEmitWhitespace(true))
+	tokens, errors := WrapLexer(l, SkipWhitespace(), record, SkipComments()).TokenizeAll()
+	if len(errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", errors)
+	}
+
+	// record runs after SkipWhitespace but before SkipComments
+	expectedSeen := []TokenType{IDENT, LINE_COMMENT, IDENT, EOF}
+	if !tokenTypesEqual(seen, expectedSeen) {
+		t.Errorf("Expected middleware to see %v, got %v", expectedSeen, seen)
+	}
+
+	expected := []Token{NewToken(IDENT, "x", 1, 1), NewToken(IDENT, "y", 2, 3)}
+	if !TokenSliceEqual(tokens, expected) {
+		t.Errorf("Expected %v, got %v", expected, tokens)
+	}
+}
+
+// Test semicolon injection at line ends
+func TestInjectSemicolons(t *testing.T) {
+	input := "x = f(a,\n  b)\nlet s = \"hi\"\nif x {\n  y\n}"
+	tokens, _ := WrapLexer(NewLexer(input), InjectSemicolons()).TokenizeAll()
+
+	expected := []TokenType{
+		IDENT, ASSIGN, IDENT, LPAREN, IDENT, COMMA,
+		IDENT, RPAREN, SEMICOLON,
+		LET, IDENT, ASSIGN, STRING, SEMICOLON,
+		IF, IDENT, LBRACE,
+		IDENT, SEMICOLON,
+		RBRACE, SEMICOLON,
+	}
+	types := make([]TokenType, len(tokens))
+	for i, tok := range tokens {
+		types[i] = tok.Type
+	}
+	if !tokenTypesEqual(types, expected) {
+		t.Fatalf("Expected %v, got %v", expected, types)
+	}
+
+	semi := tokens[8]
+	if semi.Literal != ";" || semi.Line != 2 || semi.Column != 5 {
+		t.Errorf("Expected synthetic ';' at 2:5, got %q at %d:%d", semi.Literal, semi.Line, semi.Column)
+	}
+}
+
+func tokenTypesEqual(a, b []TokenType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Test Go-style semicolon insertion
+func TestNewSemicolonInserter(t *testing.T) {
+	tokens, _ := NewSemicolonInserter(NewLexer("let x = 1\nlet y = 2")).TokenizeAll()
+
+	expected := []Token{
+		NewToken(LET, "let", 1, 1),
+		NewToken(IDENT, "x", 1, 5),
+		NewToken(ASSIGN, "=", 1, 7),
+		NewToken(NUMBER, "1", 1, 9),
+		NewToken(SEMICOLON, ";", 1, 10),
+		NewToken(LET, "let", 2, 1),
+		NewToken(IDENT, "y", 2, 5),
+		NewToken(ASSIGN, "=", 2, 7),
+		NewToken(NUMBER, "2", 2, 9),
+		NewToken(SEMICOLON, ";", 2, 10),
+	}
+	if !TokenSliceEqual(tokens, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+	for i := range expected {
+		if tokens[i].Line != expected[i].Line || tokens[i].Column != expected[i].Column {
+			t.Errorf("Token %d: expected %d:%d, got %d:%d", i, expected[i].Line, expected[i].Column, tokens[i].Line, tokens[i].Column)
+		}
+	}
+}
+
+// Test which line-ending tokens trigger insertion
+func TestNewSemicolonInserterTriggers(t *testing.T) {
+	tests := []struct {
+		input  string
+		insert bool
+	}{
+		{"return\nx", true},
+		{"break\nx", true},
+		{"i++\nx", true},
+		{"i--\nx", true},
+		{"true\nx", true},
+		{"null\nx", true},
+		{"'c'\nx", true},
+		{"`raw`\nx", true},
+		{"a[0]\nx", true},
+		{"x // trailing\ny", true},
+		{"a +\nb", false},
+		{"f(\nb", false},
+		{"if x {\ny", false},
+		{"a,\nb", false},
+	}
+
+	for _, tt := range tests {
+		tokens, _ := NewSemicolonInserter(NewLexer(tt.input)).TokenizeAll()
+		semicolons := 0
+		for _, tok := range tokens[:len(tokens)-1] {
+			if tok.Type == SEMICOLON {
+				semicolons++
+			}
+		}
+		if (semicolons == 1) != tt.insert || semicolons > 1 {
+			t.Errorf("Input %q: expected insertion %v, got %d semicolons before the last token", tt.input, tt.insert, semicolons)
+		}
+	}
+}
+
+// Test insertion after a token that spans lines uses the line it ends on
+func TestNewSemicolonInserterMultiLineToken(t *testing.T) {
+	tokens, _ := NewSemicolonInserter(NewLexer("x = `a\nb` + c\nd")).TokenizeAll()
+
+	expected := []TokenType{IDENT, ASSIGN, BACKTICK_STRING, PLUS, IDENT, SEMICOLON, IDENT, SEMICOLON}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %v", len(expected), len(tokens), tokens)
+	}
+	for i, tokType := range expected {
+		if tokens[i].Type != tokType {
+			t.Errorf("Token %d: expected %s, got %s", i, tokType, tokens[i].Type)
+		}
+	}
+
+	tokens, _ = NewSemicolonInserter(NewLexer("x = `a\nbc`\ny")).TokenizeAll()
+	if semi := tokens[3]; semi.Type != SEMICOLON || semi.Line != 2 || semi.Column != 4 {
+		t.Errorf("Expected SEMICOLON at 2:4, got %s at %d:%d", semi.Type, semi.Line, semi.Column)
+	}
+}
+
+// Test the metering middleware counts every token but EOF
+func TestMeteringMiddleware(t *testing.T) {
+	var counter int64
+	input := strings.Repeat("a ", 100)
+	w := WrapLexer(NewLexer(input), MeteringMiddleware(&counter))
+
+	tokens, _ := w.TokenizeAll()
+	w.NextToken() // a further EOF must not be counted
+
+	if len(tokens) != 100 || counter != 100 {
+		t.Errorf("Expected 100 tokens counted, got %d tokens and a count of %d", len(tokens), counter)
+	}
+}
+
+// Test the rate middleware only sleeps when tokens arrive too quickly
+func TestTokenRateMiddleware(t *testing.T) {
+	input := "a b c d e f g h i j"
+
+	start := time.Now()
+	WrapLexer(NewLexer(input), TokenRateMiddleware(1e9)).TokenizeAll()
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected no throttling at a high rate, took %v", elapsed)
+	}
+
+	// 10 tokens at 50 per second wait 9 intervals of 20ms
+	start = time.Now()
+	tokens, _ := WrapLexer(NewLexer(input), TokenRateMiddleware(50)).TokenizeAll()
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("Expected at least 180ms for 10 tokens at 50/s, took %v", elapsed)
+	}
+	if len(tokens) != 10 {
+		t.Errorf("Expected 10 tokens, got %d", len(tokens))
+	}
+}
@@ -0,0 +1,166 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Multi-File Lexing
+Tokenizes several named sources, such as the files of a project in a
+language server, keeping tokens and errors attributed to the file they
+came from. Every token carries its file name in SourceFile.
+*/
+
+package golexer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MultiFileLexer tokenizes a set of named files
+type MultiFileLexer struct {
+	opts   []Option
+	names  []string
+	files  map[string]string
+	tokens map[string][]Token
+	errors map[string]LexErrorSlice
+}
+
+// NewMultiFileLexer creates an empty multi-file lexer. The options are
+// applied to the lexer for every file.
+func NewMultiFileLexer(opts ...Option) *MultiFileLexer {
+	return &MultiFileLexer{
+		opts:  opts,
+		files: make(map[string]string),
+	}
+}
+
+// AddFile adds a named source. Names must be unique and non-empty.
+func (m *MultiFileLexer) AddFile(name, content string) error {
+	if name == "" {
+		return fmt.Errorf("file name must not be empty")
+	}
+	if _, ok := m.files[name]; ok {
+		return fmt.Errorf("file %q already added", name)
+	}
+	m.names = append(m.names, name)
+	m.files[name] = content
+	m.tokens = nil
+	m.errors = nil
+	return nil
+}
+
+// TokenizeAll tokenizes every file and returns the tokens and errors
+// keyed by file name. The results are cached until a file is added; each
+// call returns fresh copies.
+func (m *MultiFileLexer) TokenizeAll() (map[string][]Token, map[string][]*LexError) {
+	m.tokenize()
+	return m.results()
+}
+
+// tokenize lexes every file unless the results are already cached
+func (m *MultiFileLexer) tokenize() {
+	if m.tokens != nil {
+		return
+	}
+	m.tokens = make(map[string][]Token, len(m.names))
+	m.errors = make(map[string]LexErrorSlice, len(m.names))
+	for _, name := range m.names {
+		m.tokens[name], m.errors[name] = m.tokenizeFile(name)
+	}
+}
+
+// results returns copies of the cached tokens and errors so callers
+// cannot change the cache
+func (m *MultiFileLexer) results() (map[string][]Token, map[string][]*LexError) {
+	tokens := make(map[string][]Token, len(m.tokens))
+	for name, toks := range m.tokens {
+		tokens[name] = copyTokens(toks)
+	}
+	errors := make(map[string][]*LexError, len(m.errors))
+	for name, errs := range m.errors {
+		errors[name] = copyErrors(errs)
+	}
+	return tokens, errors
+}
+
+// TokenizeAllParallel is TokenizeAll with the files lexed concurrently by
+// at most workers goroutines. Configs must not be loaded while it runs,
+// since they change the shared keyword and operator tables. Like
+// TokenizeAll, it returns copies of the cached results.
+func (m *MultiFileLexer) TokenizeAllParallel(workers int) (map[string][]Token, map[string][]*LexError, error) {
+	if workers < 1 {
+		return nil, nil, fmt.Errorf("workers must be at least 1, got %d", workers)
+	}
+	if m.tokens == nil {
+		m.tokenizeParallel(workers)
+	}
+	tokens, errors := m.results()
+	return tokens, errors, nil
+}
+
+// tokenizeParallel lexes every file with at most workers goroutines and
+// caches the results
+func (m *MultiFileLexer) tokenizeParallel(workers int) {
+	tokens := make(map[string][]Token, len(m.names))
+	errors := make(map[string]LexErrorSlice, len(m.names))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	queue := make(chan string)
+	for i := 0; i < workers && i < len(m.names); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				toks, errs := m.tokenizeFile(name)
+				mu.Lock()
+				tokens[name], errors[name] = toks, errs
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, name := range m.names {
+		queue <- name
+	}
+	close(queue)
+	wg.Wait()
+
+	m.tokens, m.errors = tokens, errors
+}
+
+// tokenizeFile lexes one added file
+func (m *MultiFileLexer) tokenizeFile(name string) ([]Token, LexErrorSlice) {
+	opts := append([]Option{WithSourceName(name)}, m.opts...)
+	return NewLexer(m.files[name], opts...).TokenizeAll()
+}
+
+// AllTokens returns the tokens of every file in one slice, ordered by
+// file name and then by position
+func (m *MultiFileLexer) AllTokens() []Token {
+	m.tokenize()
+
+	var all []Token
+	for _, name := range m.names {
+		all = append(all, m.tokens[name]...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].SourceFile != all[j].SourceFile {
+			return all[i].SourceFile < all[j].SourceFile
+		}
+		return all[i].Line < all[j].Line
+	})
+	return all
+}
+
+// ErrorCount returns the number of errors across all files
+func (m *MultiFileLexer) ErrorCount() int {
+	m.tokenize()
+
+	count := 0
+	for _, errs := range m.errors {
+		count += len(errs)
+	}
+	return count
+}
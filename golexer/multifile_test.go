@@ -0,0 +1,147 @@
+package golexer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Test tokens and errors are kept per file
+func TestMultiFileLexer(t *testing.T) {
+	m := NewMultiFileLexer()
+	files := map[string]string{
+		"main.lang":  "let x = 1;\nlet y = x;",
+		"util.lang":  "fn f() { § }",
+		"empty.lang": "",
+	}
+	for _, name := range []string{"util.lang", "main.lang", "empty.lang"} {
+		if err := m.AddFile(name, files[name]); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if err := m.AddFile("main.lang", "x"); err == nil {
+		t.Errorf("Expected error for a duplicate file")
+	}
+	if err := m.AddFile("", "x"); err == nil {
+		t.Errorf("Expected error for an empty file name")
+	}
+
+	tokens, errors := m.TokenizeAll()
+	total := 0
+	for name, input := range files {
+		expected, expectedErrs := NewLexer(input).TokenizeAll()
+		if !TokenSliceEqual(tokens[name], expected) {
+			t.Errorf("File %s: expected %v, got %v", name, expected, tokens[name])
+		}
+		if len(errors[name]) != len(expectedErrs) {
+			t.Errorf("File %s: expected %d errors, got %d", name, len(expectedErrs), len(errors[name]))
+		}
+		for _, tok := range tokens[name] {
+			if tok.SourceFile != name {
+				t.Errorf("File %s: token %q has SourceFile %q", name, tok.Literal, tok.SourceFile)
+			}
+		}
+		for _, e := range errors[name] {
+			if e.SourceFile != name {
+				t.Errorf("File %s: error %q has SourceFile %q", name, e.Message, e.SourceFile)
+			}
+		}
+		total += len(errors[name])
+	}
+
+	if m.ErrorCount() != total || total != 1 {
+		t.Errorf("Expected 1 error in total, got %d (sum %d)", m.ErrorCount(), total)
+	}
+
+	all := m.AllTokens()
+	if len(all) != len(tokens["main.lang"])+len(tokens["util.lang"]) {
+		t.Fatalf("Expected all tokens combined, got %d", len(all))
+	}
+	if all[0].SourceFile != "main.lang" || all[len(all)-1].SourceFile != "util.lang" {
+		t.Errorf("Expected tokens sorted by file, got %s first and %s last", all[0].SourceFile, all[len(all)-1].SourceFile)
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].SourceFile == all[i-1].SourceFile && all[i].Line < all[i-1].Line {
+			t.Errorf("Expected tokens sorted by line within %s", all[i].SourceFile)
+		}
+	}
+
+	// Changing the results must not change the cache
+	tokens["main.lang"][0].Literal = "changed"
+	errors["util.lang"][0].Message = "changed"
+	delete(tokens, "util.lang")
+	again, againErrors := m.TokenizeAll()
+	if again["main.lang"][0].Literal != "let" || len(again["util.lang"]) == 0 || againErrors["util.lang"][0].Message == "changed" {
+		t.Errorf("Expected TokenizeAll to return copies of its cached results")
+	}
+}
+
+// multiFileSource returns a lexer with count files of roughly size bytes
+func multiFileSource(count, size int) *MultiFileLexer {
+	m := NewMultiFileLexer()
+	line := "let total = count * 3.14 + \"label\"; // note\n"
+	for i := 0; i < count; i++ {
+		content := strings.Repeat(line, size/len(line)+1)
+		if i%5 == 0 {
+			content += "§"
+		}
+		m.AddFile(fmt.Sprintf("file%02d.lang", i), content)
+	}
+	return m
+}
+
+// Test parallel tokenization matches the sequential result
+func TestTokenizeAllParallel(t *testing.T) {
+	expectedTokens, expectedErrors := multiFileSource(20, 500).TokenizeAll()
+
+	m := multiFileSource(20, 500)
+	tokens, errors, err := m.TokenizeAllParallel(4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tokens) != 20 || len(errors) != 20 {
+		t.Fatalf("Expected results for 20 files, got %d and %d", len(tokens), len(errors))
+	}
+	for name, expected := range expectedTokens {
+		if !TokenSliceEqual(tokens[name], expected) {
+			t.Errorf("File %s: tokens differ from the sequential result", name)
+		}
+		if len(errors[name]) != len(expectedErrors[name]) {
+			t.Errorf("File %s: expected %d errors, got %d", name, len(expectedErrors[name]), len(errors[name]))
+		}
+	}
+	if m.ErrorCount() != 4 {
+		t.Errorf("Expected 4 errors in total, got %d", m.ErrorCount())
+	}
+
+	// Cached results are returned as copies
+	errors["file00.lang"][0].Message = "changed"
+	if _, again, _ := m.TokenizeAllParallel(4); again["file00.lang"][0].Message == "changed" {
+		t.Errorf("Expected TokenizeAllParallel to return copies of its cached results")
+	}
+
+	if _, _, err := NewMultiFileLexer().TokenizeAllParallel(0); err == nil {
+		t.Errorf("Expected error for 0 workers")
+	}
+}
+
+func BenchmarkMultiFileSequential(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		m := multiFileSource(20, 10*1024)
+		b.StartTimer()
+		m.TokenizeAll()
+	}
+}
+
+func BenchmarkMultiFileParallel(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		m := multiFileSource(20, 10*1024)
+		b.StartTimer()
+		m.TokenizeAllParallel(4)
+	}
+}
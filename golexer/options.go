@@ -0,0 +1,190 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Lexer Options
+Functional options accepted by NewLexer and its variants. Options are
+applied before the first character is read, so they can influence how
+the whole input is scanned.
+*/
+
+package golexer
+
+// Option configures a Lexer at construction time
+type Option func(*Lexer)
+
+// WithMaxInputSize limits the input to the given number of bytes. Larger
+// inputs produce a single error followed by EOF. Zero means unlimited.
+func WithMaxInputSize(bytes int) Option {
+	return func(l *Lexer) {
+		l.maxInputSize = bytes
+	}
+}
+
+// MultiWordOperator is an operator spelled as several words, such as
+// "not in" or "is not". Words may be separated by spaces or tabs.
+type MultiWordOperator struct {
+	Words []string
+	Type  TokenType
+}
+
+// WithMultiWordOperators enables the given multi-word operators. When the
+// words appear in sequence they produce a single token of the given type.
+// Entries with fewer than two words are ignored; use keywords for those.
+func WithMultiWordOperators(ops []MultiWordOperator) Option {
+	return func(l *Lexer) {
+		l.multiWordOps = append(l.multiWordOps, ops...)
+	}
+}
+
+// WithEmitComments makes the lexer return comments as LINE_COMMENT,
+// BLOCK_COMMENT and DOC_COMMENT tokens instead of skipping them. Doc
+// comments start with /// or /**.
+func WithEmitComments(enabled bool) Option {
+	return func(l *Lexer) {
+		l.emitComments = enabled
+	}
+}
+
+// WithLineCommentPrefix makes prefix start a line comment in addition to
+// "//", as '#' does in shell and Python. It takes precedence over any
+// token configured for the same character.
+func WithLineCommentPrefix(prefix rune) Option {
+	return func(l *Lexer) {
+		l.commentChar = prefix
+	}
+}
+
+// WithSlashComments controls whether "//" starts a line comment. It is on
+// by default; when off "//" is read as two DIVIDE tokens. Block comments
+// are not affected.
+func WithSlashComments(enabled bool) Option {
+	return func(l *Lexer) {
+		l.lineComment = enabled
+	}
+}
+
+// WithEmitWhitespace makes the lexer return each run of spaces, tabs and
+// newlines as a WHITESPACE token instead of skipping it
+func WithEmitWhitespace(enabled bool) Option {
+	return func(l *Lexer) {
+		l.emitWhitespace = enabled
+	}
+}
+
+// WithEmitNewlines makes the lexer return a NEWLINE token for every "\n"
+// or "\r\n" while still skipping spaces and tabs. Consecutive newlines
+// each produce a token.
+func WithEmitNewlines(enabled bool) Option {
+	return func(l *Lexer) {
+		l.emitNewlines = enabled
+	}
+}
+
+// WithAllowDollarInIdentifiers lets identifiers start with and contain
+// '$', as in JavaScript. '$' followed by a digit is still an error.
+func WithAllowDollarInIdentifiers(enabled bool) Option {
+	return func(l *Lexer) {
+		l.allowDollar = enabled
+	}
+}
+
+// WithUnicodeIdentifiers lets identifiers continue with characters from
+// the Unicode categories Nl (letter numbers such as Ⅻ), Mn (nonspacing
+// marks such as U+0301 COMBINING ACUTE ACCENT) and Mc (spacing combining
+// marks). Identifiers must still start with a letter or underscore.
+// Letters (L*) and decimal digits (Nd) from every script are accepted
+// without this option.
+func WithUnicodeIdentifiers(enabled bool) Option {
+	return func(l *Lexer) {
+		l.unicodeIdent = enabled
+	}
+}
+
+// WithNumberSeparators allows single underscores between the digits of
+// a number, as in 1_000_000 or 0xFF_FF. An underscore right after a base
+// prefix, next to another underscore or at the end of the digits is an
+// error.
+func WithNumberSeparators(enabled bool) Option {
+	return func(l *Lexer) {
+		l.separators = enabled
+	}
+}
+
+// WithFloatSuffixes sets the type suffixes a float literal may end with.
+// The suffix is part of the FLOAT literal; Token.FloatSuffix returns it.
+// The default is "f32", "f64" and "d128"; an empty list allows none.
+func WithFloatSuffixes(suffixes []string) Option {
+	return func(l *Lexer) {
+		l.floatSuffixes = suffixes
+	}
+}
+
+// WithIntSuffixes allows an integer literal, including hex, binary and
+// octal ones, to end with one of the given type suffixes, such as Rust's
+// "u8", "i64" or "usize". A suffixed integer is an INT token whose
+// literal includes the suffix; Token.IntSuffix returns it. Integers
+// without a suffix stay NUMBER. No suffixes are allowed by default.
+func WithIntSuffixes(suffixes []string) Option {
+	return func(l *Lexer) {
+		l.intSuffixes = suffixes
+	}
+}
+
+// WithConfig adds the keywords, operators, punctuation and string
+// delimiters of c to this lexer only, like NewLexerWithConfig without the
+// file. The package tables are not changed. A nil config adds nothing.
+func WithConfig(c *Config) Option {
+	return func(l *Lexer) {
+		if c != nil {
+			l.applyConfig(c)
+		}
+	}
+}
+
+// WithAnnotationTokens controls whether '@' followed by an identifier is
+// read as a single ANNOTATION token. It is on by default; when off the
+// same input produces AT followed by the identifier.
+func WithAnnotationTokens(enabled bool) Option {
+	return func(l *Lexer) {
+		l.annotations = enabled
+	}
+}
+
+// WithTokenCache keeps every token NextToken returns, so TokensOnLine can
+// serve lines the lexer has already tokenized without scanning them again
+func WithTokenCache(enabled bool) Option {
+	return func(l *Lexer) {
+		l.cacheTokens = enabled
+	}
+}
+
+// WithSourceName sets the file name recorded in the SourceFile of each
+// token and error
+func WithSourceName(name string) Option {
+	return func(l *Lexer) {
+		l.sourceName = name
+	}
+}
+
+// ErrorMode controls how the lexer reacts to malformed input
+type ErrorMode int
+
+const (
+	// LenientMode collects errors and keeps tokenizing (the default)
+	LenientMode ErrorMode = iota
+	// StrictMode stops at the first error: NextToken returns EOF from then on
+	StrictMode
+	// SilentMode reports no errors and drops ILLEGAL tokens, giving a
+	// best-effort stream for tools such as syntax highlighters
+	SilentMode
+)
+
+// WithErrorMode sets how the lexer handles malformed input
+func WithErrorMode(m ErrorMode) Option {
+	return func(l *Lexer) {
+		l.errorMode = m
+	}
+}
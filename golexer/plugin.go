@@ -0,0 +1,65 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Token Handler Plugins
+Lets users add their own token types without forking the lexer.
+Registered handlers are consulted before the built-in dispatch, so a
+handler can also take over characters the lexer would otherwise treat
+as operators or errors.
+*/
+
+package golexer
+
+// TokenHandler recognizes and reads a custom token. Match is called with
+// the current character and a function that peeks n characters ahead
+// (peek(1) is the next character). If it returns true, Read must consume
+// the token using Advance, leaving the lexer on the first character after
+// it, and return the token's type and literal. The lexer fills in the
+// token's position.
+type TokenHandler interface {
+	Match(ch rune, peek func(n int) rune) bool
+	Read(l *Lexer) Token
+}
+
+// RegisterTokenHandler adds a handler. Handlers are tried in the order
+// they were registered and the first match wins.
+func (l *Lexer) RegisterTokenHandler(h TokenHandler) {
+	l.handlers = append(l.handlers, h)
+}
+
+// CurrentChar returns the character under the cursor, or 0 at end of input.
+// Use IsAtEOF to tell the end of input from a NUL character.
+func (l *Lexer) CurrentChar() rune {
+	return l.ch
+}
+
+// Peek returns the character n positions after the cursor
+func (l *Lexer) Peek(n int) rune {
+	return l.peekCharN(n)
+}
+
+// Advance moves the cursor to the next character
+func (l *Lexer) Advance() {
+	l.readChar()
+}
+
+// tryHandlers runs the first registered handler that matches the current
+// character. A handler that consumes nothing still advances one character
+// so the lexer cannot stall. Handlers are not consulted at end of input.
+func (l *Lexer) tryHandlers(line, column, offset int) (Token, bool) {
+	for _, h := range l.handlers {
+		if !h.Match(l.ch, l.peekCharN) {
+			continue
+		}
+		tok := h.Read(l)
+		if l.position == offset {
+			l.readChar()
+		}
+		tok.Line, tok.Column, tok.Offset = line, column, offset
+		return tok, true
+	}
+	return Token{}, false
+}
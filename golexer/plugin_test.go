@@ -0,0 +1,73 @@
+package golexer
+
+import (
+	"testing"
+)
+
+const DOLLAR_IDENT TokenType = "DOLLAR_IDENT"
+
+// dollarHandler reads $name as a single DOLLAR_IDENT token
+type dollarHandler struct{}
+
+func (dollarHandler) Match(ch rune, peek func(n int) rune) bool {
+	return ch == '$' && isLetter(peek(1))
+}
+
+func (dollarHandler) Read(l *Lexer) Token {
+	literal := string(l.CurrentChar())
+	l.Advance()
+	for isLetter(l.CurrentChar()) || isDigit(l.CurrentChar()) {
+		literal += string(l.CurrentChar())
+		l.Advance()
+	}
+	return Token{Type: DOLLAR_IDENT, Literal: literal}
+}
+
+// Test a registered token handler
+func TestRegisterTokenHandler(t *testing.T) {
+	input := "let $total = count + $n2 - 1"
+	l := NewLexer(input)
+	l.RegisterTokenHandler(dollarHandler{})
+	tokens, errors := l.TokenizeAll()
+
+	expected := []Token{
+		NewToken(LET, "let", 1, 1),
+		NewToken(DOLLAR_IDENT, "$total", 1, 5),
+		NewToken(ASSIGN, "=", 1, 12),
+		NewToken(IDENT, "count", 1, 14),
+		NewToken(PLUS, "+", 1, 20),
+		NewToken(DOLLAR_IDENT, "$n2", 1, 22),
+		NewToken(MINUS, "-", 1, 26),
+		NewToken(NUMBER, "1", 1, 28),
+	}
+	if !TokenSliceEqual(tokens, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+	for i := range expected {
+		if tokens[i].Column != expected[i].Column {
+			t.Errorf("Token %d: expected column %d, got %d", i, expected[i].Column, tokens[i].Column)
+		}
+	}
+	if tokens[1].SourceText(input) != "$total" {
+		t.Errorf("Expected handler token to span its input, got %q", tokens[1].SourceText(input))
+	}
+	if len(errors) != 0 {
+		t.Errorf("Unexpected errors: %v", errors)
+	}
+}
+
+// stallHandler matches but consumes nothing
+type stallHandler struct{}
+
+func (stallHandler) Match(ch rune, peek func(n int) rune) bool { return ch == '#' }
+func (stallHandler) Read(l *Lexer) Token                       { return Token{Type: "HASH", Literal: "#"} }
+
+// Test that a handler that consumes nothing cannot stall the lexer
+func TestRegisterTokenHandlerProgress(t *testing.T) {
+	l := NewLexer("##x")
+	l.RegisterTokenHandler(stallHandler{})
+	tokens, _ := l.TokenizeAll()
+	if len(tokens) != 3 || tokens[0].Type != "HASH" || tokens[2].Type != IDENT {
+		t.Errorf("Expected HASH HASH IDENT, got %v", tokens)
+	}
+}
@@ -0,0 +1,264 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Positions and Lines
+Converts between the rune-based columns reported in tokens and byte
+offsets within a line, and scans single lines for tools such as syntax
+highlighters. Line start offsets are computed once, on first use.
+*/
+
+package golexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// lineText returns the text of a 1-based line without its newline
+func (l *Lexer) lineText(line int) (string, error) {
+	if _, err := l.lineStart(line); err != nil {
+		return "", err
+	}
+	start := l.lineOffsets[line-1]
+	end := len(l.input)
+	if line < len(l.lineOffsets) {
+		end = l.lineOffsets[line] - 1
+	}
+	return l.input[start:end], nil
+}
+
+// lineStart returns the byte offset where a 1-based line starts
+func (l *Lexer) lineStart(line int) (int, error) {
+	if l.lineOffsets == nil {
+		l.lineOffsets = []int{0}
+		for i := 0; i < len(l.input); i++ {
+			if l.input[i] == '\n' {
+				l.lineOffsets = append(l.lineOffsets, i+1)
+			}
+		}
+	}
+
+	if line < 1 || line > len(l.lineOffsets) {
+		return 0, fmt.Errorf("line %d out of range 1-%d", line, len(l.lineOffsets))
+	}
+	return l.lineOffsets[line-1], nil
+}
+
+// offsetPosition returns the 1-based line and rune column of a byte offset
+func (l *Lexer) offsetPosition(offset int) (int, int) {
+	l.lineStart(1)
+	line := sort.SearchInts(l.lineOffsets, offset+1)
+	start := l.lineOffsets[line-1]
+	return line, utf8.RuneCountInString(l.input[start:offset]) + 1
+}
+
+// ColumnToByteOffset returns the byte offset within a line of the given
+// 1-based rune column. The column just past the last rune is allowed.
+func (l *Lexer) ColumnToByteOffset(line, col int) (int, error) {
+	text, err := l.lineText(line)
+	if err != nil {
+		return 0, err
+	}
+	if col < 1 {
+		return 0, fmt.Errorf("column %d out of range on line %d", col, line)
+	}
+
+	offset := 0
+	for c := 1; c < col; c++ {
+		if offset >= len(text) {
+			return 0, fmt.Errorf("column %d out of range on line %d", col, line)
+		}
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return offset, nil
+}
+
+// ByteOffsetToColumn returns the 1-based rune column of a byte offset
+// within a line. The offset must fall on the start of a rune or at the
+// end of the line.
+func (l *Lexer) ByteOffsetToColumn(line, byteOffset int) (int, error) {
+	text, err := l.lineText(line)
+	if err != nil {
+		return 0, err
+	}
+	if byteOffset < 0 || byteOffset > len(text) {
+		return 0, fmt.Errorf("byte offset %d out of range on line %d", byteOffset, line)
+	}
+	if byteOffset < len(text) && !utf8.RuneStart(text[byteOffset]) {
+		return 0, fmt.Errorf("byte offset %d on line %d is inside a character", byteOffset, line)
+	}
+	return utf8.RuneCountInString(text[:byteOffset]) + 1, nil
+}
+
+// TokensOnLine returns the tokens that start on the given line without
+// advancing the lexer. A lexer created WithTokenCache serves the line from
+// the tokens it has already returned, once it has returned EOF or a token
+// on a later line. Other lines are scanned by a copy of the lexer from the
+// start of the line, or from the end of a returned token that runs onto
+// it. A line the lexer has not reached that starts inside a multi-line
+// string, comment or heredoc is scanned as if it were ordinary code.
+func (l *Lexer) TokensOnLine(line int) []Token {
+	start, err := l.lineStart(line)
+	if err != nil {
+		return nil
+	}
+
+	cache := l.tokenCache
+	if l.cacheDone || (len(cache) > 0 && cache[len(cache)-1].Line > line) {
+		first := sort.Search(len(cache), func(i int) bool { return cache[i].Line >= line })
+		end := first
+		for end < len(cache) && cache[end].Line == line {
+			end++
+		}
+		if first == end {
+			return nil
+		}
+		return copyTokens(cache[first:end])
+	}
+
+	// Resume after a returned token that starts before the line and ends on it
+	from, lastType := start, TokenType("")
+	if i := sort.Search(len(cache), func(i int) bool { return cache[i].Offset >= start }); i > 0 {
+		if prev := cache[i-1]; prev.EndOffset > start {
+			from, lastType = prev.EndOffset, prev.Type
+		}
+	}
+	fromLine, fromColumn := l.offsetPosition(from)
+
+	scan := *l
+	scan.errors = make([]*LexError, 0)
+	scan.tokenBuffer = nil
+	scan.fstrings = nil
+	scan.atLineStart = from == start
+	scan.afterNewline = from == start && line > 1
+	scan.readPosition = from
+	scan.atEOF = false
+	scan.lastType = lastType
+	scan.heredoc = nil
+	scan.seenTypes = nil
+	scan.seenTypeSet = nil
+	scan.cacheTokens = false
+	scan.line = fromLine
+	scan.column = fromColumn - 1
+	scan.readChar()
+
+	var tokens []Token
+	for {
+		tok := scan.NextToken()
+		if tok.Type == EOF || tok.Line != line {
+			break
+		}
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+// ScanLine returns the tokens that start on one line of input
+func ScanLine(input string, lineNumber int) []Token {
+	return NewLexer(input).TokensOnLine(lineNumber)
+}
+
+// TokenAtOffset returns the token whose source text contains the byte
+// offset. tokens must be sorted by Offset, as returned by TokenizeAll.
+// It returns false for offsets in whitespace, comments or past the end.
+func TokenAtOffset(tokens []Token, offset int) (Token, bool) {
+	i := sort.Search(len(tokens), func(i int) bool { return tokens[i].EndOffset > offset })
+	if i < len(tokens) && tokens[i].Offset <= offset {
+		return tokens[i], true
+	}
+	return Token{}, false
+}
+
+// TokenAtPosition returns the token covering a 1-based line and rune
+// column. tokens must be in source order. A token's width comes from its
+// EndOffset, so escaped strings and heredoc tags are covered in full.
+func TokenAtPosition(tokens []Token, line, col int) (Token, bool) {
+	i := sort.Search(len(tokens), func(i int) bool {
+		return tokens[i].Line > line || (tokens[i].Line == line && tokens[i].Column > col)
+	})
+	if i == 0 {
+		return Token{}, false
+	}
+
+	tok := tokens[i-1]
+	if tok.Line == line && col < tok.Column+tokenWidth(tok) {
+		return tok, true
+	}
+	return Token{}, false
+}
+
+// tokenWidth returns the number of runes a token spans, including quotes.
+// The byte span is converted to runes using the multi-byte runes of the
+// literal. Tokens without an EndOffset fall back to the literal plus its
+// quotes.
+func tokenWidth(tok Token) int {
+	if tok.EndOffset > tok.Offset {
+		return tok.EndOffset - tok.Offset - (len(tok.Literal) - utf8.RuneCountInString(tok.Literal))
+	}
+
+	width := utf8.RuneCountInString(tok.Literal)
+	switch tok.Type {
+	case STRING, CHAR, BACKTICK_STRING:
+		width += 2
+	case RAW_STRING, BYTE_STRING:
+		width += 3
+	case TRIPLE_STRING, TRIPLE_BACKTICK:
+		width += 6
+	case FSTRING_MIDDLE:
+		// Braces in f-string text are written doubled
+		width += strings.Count(tok.Literal, "{") + strings.Count(tok.Literal, "}")
+	}
+	return width
+}
+
+// NormalizeTokenPositions returns a copy of tokens with Line, Column,
+// Offset and EndOffset recomputed as if every "\r\n" in source were "\n",
+// so fixtures lexed from CRLF files match those lexed on Unix. Positions
+// are derived from each token's Offset into source; tokens is not modified.
+func NormalizeTokenPositions(tokens []Token, source string) []Token {
+	var crlfs, newlines []int
+	for i := 0; i < len(source); i++ {
+		if source[i] != '\n' {
+			continue
+		}
+		if i > 0 && source[i-1] == '\r' {
+			crlfs = append(crlfs, i-1)
+		}
+		newlines = append(newlines, i-len(crlfs))
+	}
+	normalized := strings.ReplaceAll(source, "\r\n", "\n")
+
+	// toLF maps an offset in source to the same place in normalized
+	toLF := func(offset int) int {
+		return offset - sort.SearchInts(crlfs, offset)
+	}
+
+	result := make([]Token, len(tokens))
+	for i, tok := range tokens {
+		if tok.Offset < 0 || tok.Offset > len(source) {
+			result[i] = tok
+			continue
+		}
+		offset := toLF(tok.Offset)
+		line := sort.SearchInts(newlines, offset)
+		lineStart := 0
+		if line > 0 {
+			lineStart = newlines[line-1] + 1
+		}
+
+		tok.Line = line + 1
+		tok.Column = utf8.RuneCountInString(normalized[lineStart:offset]) + 1
+		tok.Offset = offset
+		if tok.EndOffset >= 0 && tok.EndOffset <= len(source) {
+			tok.EndOffset = toLF(tok.EndOffset)
+		}
+		result[i] = tok
+	}
+	return result
+}
@@ -0,0 +1,220 @@
+package golexer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Test conversion between rune columns and byte offsets
+func TestColumnByteOffsetConversion(t *testing.T) {
+	l := NewLexer("let x;\n€y = 1\n")
+
+	tests := []struct {
+		line, column, offset int
+	}{
+		{1, 1, 0},
+		{1, 5, 4},
+		{2, 1, 0},
+		{2, 2, 3},
+		{2, 3, 4},
+		{2, 7, 8}, // just past the end of the line
+		{3, 1, 0},
+	}
+
+	for _, tt := range tests {
+		offset, err := l.ColumnToByteOffset(tt.line, tt.column)
+		if err != nil || offset != tt.offset {
+			t.Errorf("Line %d column %d: expected offset %d, got %d (%v)", tt.line, tt.column, tt.offset, offset, err)
+		}
+		column, err := l.ByteOffsetToColumn(tt.line, tt.offset)
+		if err != nil || column != tt.column {
+			t.Errorf("Line %d offset %d: expected column %d, got %d (%v)", tt.line, tt.offset, tt.column, column, err)
+		}
+	}
+}
+
+// Test out of range positions are rejected
+func TestColumnByteOffsetErrors(t *testing.T) {
+	l := NewLexer("€y\nz")
+
+	if _, err := l.ColumnToByteOffset(0, 1); err == nil {
+		t.Errorf("Expected error for line 0")
+	}
+	if _, err := l.ColumnToByteOffset(3, 1); err == nil {
+		t.Errorf("Expected error for line past the end")
+	}
+	if _, err := l.ColumnToByteOffset(1, 4); err == nil {
+		t.Errorf("Expected error for column past the end of the line")
+	}
+	if _, err := l.ColumnToByteOffset(1, 0); err == nil {
+		t.Errorf("Expected error for column 0")
+	}
+	if _, err := l.ByteOffsetToColumn(1, 1); err == nil {
+		t.Errorf("Expected error for an offset inside '€'")
+	}
+	if _, err := l.ByteOffsetToColumn(2, 2); err == nil {
+		t.Errorf("Expected error for an offset past the end of the line")
+	}
+}
+
+// Test scanning single lines matches tokenizing the whole input
+func TestScanLine(t *testing.T) {
+	input := "let x = 5;\n\n  fn f(a) {\n    return a * \"s\";\n}"
+	all, _ := NewLexer(input).TokenizeAll()
+
+	for line := 1; line <= 5; line++ {
+		expected := TokensOnLine(all, line)
+		got := ScanLine(input, line)
+		if len(got) != len(expected) {
+			t.Errorf("Line %d: expected %v, got %v", line, expected, got)
+			continue
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Errorf("Line %d[%d]: expected %+v, got %+v", line, i, expected[i], got[i])
+			}
+		}
+	}
+
+	if tokens := ScanLine(input, 6); tokens != nil {
+		t.Errorf("Expected nil for a line past the end, got %v", tokens)
+	}
+
+	// The lexer scanned from is left where it was
+	l := NewLexer(input)
+	l.NextToken()
+	l.TokensOnLine(4)
+	if tok := l.NextToken(); tok.Literal != "x" {
+		t.Errorf("Expected lexer to continue at 'x', got %q", tok.Literal)
+	}
+}
+
+// Test lines are served from the token cache, including lines that start
+// inside a multi-line token
+func TestTokensOnLineFromCache(t *testing.T) {
+	input := "let s = `one\ntwo` + x;\n/* a\nb */ y\nz = <<END\nbody\nEND\nw"
+	all, _ := NewLexer(input).TokenizeAll()
+
+	l := NewLexer(input, WithTokenCache(true))
+	l.TokenizeAll()
+	for line := 1; line <= 8; line++ {
+		expected := TokensOnLine(all, line)
+		if got := l.TokensOnLine(line); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Line %d: expected %v, got %v", line, expected, got)
+		}
+	}
+
+	// A line the lexer is part way through resumes after the token that
+	// runs onto it
+	l = NewLexer(input, WithTokenCache(true))
+	for tok := l.NextToken(); tok.Type != BACKTICK_STRING; tok = l.NextToken() {
+	}
+	if got, expected := l.TokensOnLine(2), TokensOnLine(all, 2); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Line 2: expected %v, got %v", expected, got)
+	}
+	if tok := l.NextToken(); tok.Type != PLUS {
+		t.Errorf("Expected the lexer to continue at '+', got %s", tok.Type)
+	}
+
+	// Rewind clears the cache
+	l.Rewind()
+	if l.tokenCache != nil || l.cacheDone {
+		t.Errorf("Expected Rewind to clear the token cache")
+	}
+}
+
+// Test finding the token under a cursor
+func TestTokenAtOffsetAndPosition(t *testing.T) {
+	input := "let name = \"hé\";\n  x >= 10"
+	tokens, _ := NewLexer(input).TokenizeAll()
+
+	tests := []struct {
+		offset       int
+		line, column int
+		literal      string
+		found        bool
+	}{
+		{0, 1, 1, "let", true},
+		{2, 1, 3, "let", true},
+		{3, 1, 4, "", false}, // space between tokens
+		{5, 1, 6, "name", true},
+		{11, 1, 12, "hé", true}, // opening quote
+		{15, 1, 15, "hé", true}, // closing quote
+		{16, 1, 16, ";", true},
+		{17, 2, 1, "", false}, // indentation
+		{22, 2, 5, ">=", true},
+		{25, 2, 8, "10", true},
+		{27, 2, 10, "", false}, // end of input
+	}
+
+	for _, tt := range tests {
+		tok, ok := TokenAtOffset(tokens, tt.offset)
+		if ok != tt.found || tok.Literal != tt.literal {
+			t.Errorf("Offset %d: expected %q (%v), got %q (%v)", tt.offset, tt.literal, tt.found, tok.Literal, ok)
+		}
+		tok, ok = TokenAtPosition(tokens, tt.line, tt.column)
+		if ok != tt.found || tok.Literal != tt.literal {
+			t.Errorf("Position %d:%d: expected %q (%v), got %q (%v)", tt.line, tt.column, tt.literal, tt.found, tok.Literal, ok)
+		}
+	}
+
+	if _, ok := TokenAtOffset(nil, 0); ok {
+		t.Errorf("Expected no token in an empty stream")
+	}
+	if _, ok := TokenAtPosition(tokens, 3, 1); ok {
+		t.Errorf("Expected no token past the last line")
+	}
+
+	// Without an EndOffset, doubled f-string braces are counted twice
+	if width := tokenWidth(Token{Type: FSTRING_MIDDLE, Literal: "{a}"}); width != 5 {
+		t.Errorf("Expected width 5 for f-string text \"{{a}}\", got %d", width)
+	}
+	if length := semanticTokenLength(Token{Type: FSTRING_MIDDLE, Literal: "{a}"}); length != 5 {
+		t.Errorf("Expected length 5 for f-string text \"{{a}}\", got %d", length)
+	}
+
+	// Widths come from the source, not the unescaped literal
+	tokens, _ = NewLexer("x = \"a\\tb\" + <<END\nbody\nEND").TokenizeAll()
+	if tok, ok := TokenAtPosition(tokens, 1, 10); !ok || tok.Type != STRING {
+		t.Errorf("Expected the escaped string at its closing quote, got %v (%v)", tok, ok)
+	}
+	if tok, ok := TokenAtPosition(tokens, 1, 18); !ok || tok.Type != HEREDOC {
+		t.Errorf("Expected the heredoc at the end of its tag, got %v (%v)", tok, ok)
+	}
+}
+
+// Test normalizing positions from CRLF input
+func TestNormalizeTokenPositions(t *testing.T) {
+	lf := "let x = 1;\nif (x > 0) {\n  print(\"hé\");\n\n}\n"
+	crlf := strings.ReplaceAll(lf, "\n", "\r\n")
+
+	expected, _ := NewLexer(lf).TokenizeAll()
+	tokens, _ := NewLexer(crlf).TokenizeAll()
+	original := append([]Token(nil), tokens...)
+
+	normalized := NormalizeTokenPositions(tokens, crlf)
+	if len(normalized) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d", len(expected), len(normalized))
+	}
+	for i := range expected {
+		want, got := expected[i], normalized[i]
+		if got.Type != want.Type || got.Literal != want.Literal || got.Line != want.Line ||
+			got.Column != want.Column || got.Offset != want.Offset || got.EndOffset != want.EndOffset {
+			t.Errorf("Token %d: expected %s %q at %d:%d [%d,%d), got %s %q at %d:%d [%d,%d)",
+				i, want.Type, want.Literal, want.Line, want.Column, want.Offset, want.EndOffset,
+				got.Type, got.Literal, got.Line, got.Column, got.Offset, got.EndOffset)
+		}
+	}
+	if !reflect.DeepEqual(tokens, original) {
+		t.Errorf("Expected the input tokens to be left unchanged")
+	}
+
+	// LF-only input is already normalized
+	if got := NormalizeTokenPositions(expected, lf); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected LF positions unchanged, got %v", got)
+	}
+	if got := NormalizeTokenPositions(nil, crlf); len(got) != 0 {
+		t.Errorf("Expected no tokens, got %v", got)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Profiling Hooks
+Reports how long the lexer spends in NextToken and in the scanners for
+identifiers, numbers, strings and operators, to help track down
+performance regressions. Without a profiler the cost is a nil check per
+call.
+*/
+
+package golexer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profiler receives the duration of each profiled lexer method call.
+// Nested calls are recorded separately, so NextToken includes the time
+// of the scanners it calls.
+type Profiler interface {
+	Record(method string, duration time.Duration)
+}
+
+// WithProfiler reports method timings to p
+func WithProfiler(p Profiler) Option {
+	return func(l *Lexer) {
+		l.profiler = p
+	}
+}
+
+// profile records the time since start for a method
+func (l *Lexer) profile(method string, start time.Time) {
+	l.profiler.Record(method, time.Since(start))
+}
+
+// methodStats accumulates the calls to one method
+type methodStats struct {
+	calls int
+	total time.Duration
+}
+
+// SimpleProfiler counts calls and total time per method. It is safe for
+// use by several lexers at once.
+type SimpleProfiler struct {
+	mu    sync.Mutex
+	stats map[string]*methodStats
+}
+
+// NewSimpleProfiler creates an empty profiler
+func NewSimpleProfiler() *SimpleProfiler {
+	return &SimpleProfiler{stats: make(map[string]*methodStats)}
+}
+
+// Record adds one call of method taking duration
+func (p *SimpleProfiler) Record(method string, duration time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.stats[method]
+	if !ok {
+		s = &methodStats{}
+		p.stats[method] = s
+	}
+	s.calls++
+	s.total += duration
+}
+
+// Calls returns the number of recorded calls of method
+func (p *SimpleProfiler) Calls(method string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if s, ok := p.stats[method]; ok {
+		return s.calls
+	}
+	return 0
+}
+
+// Report returns one line per method with its calls, total and average
+// time, slowest first
+func (p *SimpleProfiler) Report() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	methods := make([]string, 0, len(p.stats))
+	for method := range p.stats {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		a, b := p.stats[methods[i]], p.stats[methods[j]]
+		if a.total != b.total {
+			return a.total > b.total
+		}
+		return methods[i] < methods[j]
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-20s %10s %14s %12s\n", "method", "calls", "total", "avg")
+	for _, method := range methods {
+		s := p.stats[method]
+		fmt.Fprintf(&sb, "%-20s %10d %14v %12v\n", method, s.calls, s.total, s.total/time.Duration(s.calls))
+	}
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+package golexer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test the simple profiler records the lexer's methods
+func TestSimpleProfiler(t *testing.T) {
+	p := NewSimpleProfiler()
+	tokens, _ := NewLexer(`let name = "value"; x = 42 + y;`, WithProfiler(p)).TokenizeAll()
+
+	if calls := p.Calls("NextToken"); calls != len(tokens)+1 {
+		t.Errorf("Expected %d NextToken calls, got %d", len(tokens)+1, calls)
+	}
+	expected := map[string]int{"readIdentifier": 4, "readString": 1, "readNumber": 1}
+	for method, calls := range expected {
+		if p.Calls(method) != calls {
+			t.Errorf("Method %s: expected %d calls, got %d", method, calls, p.Calls(method))
+		}
+	}
+
+	report := p.Report()
+	for _, method := range []string{"NextToken", "readIdentifier", "readString"} {
+		if !strings.Contains(report, method) {
+			t.Errorf("Expected report to contain %s, got:\n%s", method, report)
+		}
+	}
+	if p.Calls("readHeredoc") != 0 || strings.Contains(report, "readHeredoc") {
+		t.Errorf("Expected no entry for methods that were not called")
+	}
+}
+
+// recordingProfiler keeps every recorded method name
+type recordingProfiler []string
+
+func (r *recordingProfiler) Record(method string, duration time.Duration) {
+	*r = append(*r, method)
+}
+
+// Test custom profilers receive nested calls before the enclosing call
+func TestCustomProfiler(t *testing.T) {
+	var r recordingProfiler
+	NewLexer("abc", WithProfiler(&r)).NextToken()
+
+	expected := []string{"readIdentifier", "NextToken"}
+	if strings.Join(r, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, r)
+	}
+}
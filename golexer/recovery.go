@@ -0,0 +1,41 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Panic-Mode Recovery
+Helpers for parsers that recover from a syntax error by discarding
+tokens up to a synchronization point such as a semicolon or closing
+brace. Errors found in the skipped tokens are still collected.
+*/
+
+package golexer
+
+// isSyncToken reports whether tokType is one of the lexer's SyncTokens
+func (l *Lexer) isSyncToken(tokType TokenType) bool {
+	for _, sync := range l.SyncTokens {
+		if tokType == sync {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipToSync discards tokens until one whose type is in SyncTokens and
+// returns it. Since no token follows EOF, the end of input also stops
+// the skip and EOF is returned.
+func (l *Lexer) SkipToSync() Token {
+	return l.SkipToSyncOrEOF()
+}
+
+// SkipToSyncOrEOF discards tokens until one whose type is in SyncTokens
+// or EOF and returns that token
+func (l *Lexer) SkipToSyncOrEOF() Token {
+	for {
+		tok := l.NextToken()
+		if tok.Type == EOF || l.isSyncToken(tok.Type) {
+			return tok
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package golexer
+
+import (
+	"testing"
+)
+
+// Test skipping to the next synchronization token after an error
+func TestSkipToSync(t *testing.T) {
+	l := NewLexer("let x = 1 + § 2 * 0xZZ;\nlet y = {a}; z")
+	l.SyncTokens = []TokenType{SEMICOLON, RBRACE}
+
+	for i := 0; i < 4; i++ {
+		l.NextToken() // let x = 1
+	}
+
+	tok := l.SkipToSync()
+	if tok.Type != SEMICOLON || tok.Line != 1 {
+		t.Fatalf("Expected SEMICOLON on line 1, got %s on line %d", tok.Type, tok.Line)
+	}
+	if len(l.GetErrors()) != 2 {
+		t.Errorf("Expected errors from skipped tokens to be kept, got %v", l.GetErrors())
+	}
+
+	if tok := l.NextToken(); tok.Type != LET || tok.Line != 2 {
+		t.Errorf("Expected LET after the sync point, got %s", tok.Type)
+	}
+
+	if tok := l.SkipToSync(); tok.Type != RBRACE {
+		t.Errorf("Expected RBRACE, got %s", tok.Type)
+	}
+	if tok := l.SkipToSync(); tok.Type != SEMICOLON {
+		t.Errorf("Expected SEMICOLON, got %s", tok.Type)
+	}
+	if tok := l.SkipToSyncOrEOF(); tok.Type != EOF {
+		t.Errorf("Expected EOF without further sync tokens, got %s", tok.Type)
+	}
+}
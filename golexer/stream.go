@@ -0,0 +1,120 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Token Stream Output
+Writes tokens to an io.Writer as they are produced, one token per line,
+so large inputs can be piped to other tools without holding every token
+in memory. Each line has four tab-separated fields:
+
+	TYPE	"literal"	line	column
+
+The literal is Go-quoted so tabs and newlines inside strings survive.
+ReadTokenStream parses the same format back into tokens. Byte offsets
+are not part of the format and are zero after a round trip.
+*/
+
+package golexer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteTo streams every remaining token to w, one per line, and returns
+// the number of bytes written along with the first write error. It
+// implements io.WriterTo.
+func (l *Lexer) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+
+	for {
+		tok := l.NextToken()
+		if tok.Type == EOF {
+			break
+		}
+
+		n, err := io.WriteString(w, formatStreamLine(tok))
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// formatStreamLine formats a single token in the stream format
+func formatStreamLine(tok Token) string {
+	return string(tok.Type) + "\t" + strconv.Quote(tok.Literal) + "\t" +
+		strconv.Itoa(tok.Line) + "\t" + strconv.Itoa(tok.Column) + "\n"
+}
+
+// ReadTokenStream parses tokens written by WriteTo. It returns an error
+// naming the offending line if any line is malformed.
+func ReadTokenStream(r io.Reader) ([]Token, error) {
+	var tokens []Token
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 4 {
+			return tokens, fmt.Errorf("malformed token stream at line %d: expected 4 fields, got %d", lineNum, len(fields))
+		}
+
+		literal, err := strconv.Unquote(fields[1])
+		if err != nil {
+			return tokens, fmt.Errorf("malformed token stream at line %d: invalid literal %s", lineNum, fields[1])
+		}
+		line, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return tokens, fmt.Errorf("malformed token stream at line %d: invalid line %q", lineNum, fields[2])
+		}
+		column, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return tokens, fmt.Errorf("malformed token stream at line %d: invalid column %q", lineNum, fields[3])
+		}
+
+		tokens = append(tokens, Token{
+			Type:    TokenType(fields[0]),
+			Literal: literal,
+			Line:    line,
+			Column:  column,
+		})
+	}
+
+	return tokens, scanner.Err()
+}
+
+// RoundTrip lexes input, streams the tokens through a pipe with WriteTo
+// and reads them back with ReadTokenStream
+func RoundTrip(input string) ([]Token, error) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, err := NewLexer(input).WriteTo(pw)
+		pw.CloseWithError(err)
+	}()
+
+	tokens, err := ReadTokenStream(pr)
+	pr.Close()
+	return tokens, err
+}
+
+// PrettyPrint formats tokens as an aligned table, one token per line
+func PrettyPrint(tokens []Token) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		fmt.Fprintf(&sb, "Type: %-15s Literal: %-15s Line: %2d Column: %2d\n",
+			tok.Type.String(), fmt.Sprintf("'%s'", tok.Literal), tok.Line, tok.Column)
+	}
+	return sb.String()
+}
@@ -0,0 +1,156 @@
+package golexer
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Test WriteTo output matches TokenizeAll
+func TestWriteTo(t *testing.T) {
+	input := "let msg = \"tab\\there\";\nx >= 0x1F"
+	expected, _ := NewLexer(input).TokenizeAll()
+
+	var buf bytes.Buffer
+	n, err := NewLexer(input).WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("Expected %d bytes reported, got %d", buf.Len(), n)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d", len(expected), len(lines))
+	}
+
+	for i, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			t.Fatalf("Line %d: expected 4 fields, got %d", i, len(fields))
+		}
+		literal, err := strconv.Unquote(fields[1])
+		if err != nil {
+			t.Fatalf("Line %d: bad literal %s", i, fields[1])
+		}
+		lineNum, _ := strconv.Atoi(fields[2])
+		column, _ := strconv.Atoi(fields[3])
+
+		tok := expected[i]
+		if TokenType(fields[0]) != tok.Type || literal != tok.Literal || lineNum != tok.Line || column != tok.Column {
+			t.Errorf("Line %d: got %q, expected %+v", i, line, tok)
+		}
+	}
+}
+
+type failingWriter struct{ limit int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.limit <= 0 {
+		return 0, errors.New("write failed")
+	}
+	w.limit--
+	return len(p), nil
+}
+
+// Test WriteTo stops at the first write error
+func TestWriteToError(t *testing.T) {
+	_, err := NewLexer("a b c d").WriteTo(&failingWriter{limit: 2})
+	if err == nil || err.Error() != "write failed" {
+		t.Errorf("Expected write error, got %v", err)
+	}
+}
+
+// Test ReadTokenStream rejects malformed lines
+func TestReadTokenStreamMalformed(t *testing.T) {
+	tests := []string{
+		"IDENT\t\"x\"\t1\n",
+		"IDENT\tx\t1\t1\n",
+		"IDENT\t\"x\"\tone\t1\n",
+		"IDENT\t\"x\"\t1\t1\t\n",
+	}
+
+	for _, input := range tests {
+		if _, err := ReadTokenStream(strings.NewReader(input)); err == nil {
+			t.Errorf("Input %q: expected error", input)
+		}
+	}
+}
+
+// Test random sources survive a write/read round trip
+func TestRoundTrip(t *testing.T) {
+	fragments := []string{
+		"let", "x", "=", "42", "3.14", "0xFF", "\"str\\n\"", "'c'", "`raw\tstr`",
+		"+=", "&&", "->", "{", "}", "(", ")", ";", " ", "\n", "\t", "// c\n", "/* b */",
+		"é", "\"${a + 1}\"", "@", "123abc",
+	}
+	rng := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 100; i++ {
+		var sb strings.Builder
+		for j := 0; j < 5+rng.Intn(30); j++ {
+			sb.WriteString(fragments[rng.Intn(len(fragments))])
+			sb.WriteString(" ")
+		}
+		input := sb.String()
+
+		expected, _ := NewLexer(input).TokenizeAll()
+		got, err := RoundTrip(input)
+		if err != nil {
+			t.Fatalf("Input %q: unexpected error %v", input, err)
+		}
+		if len(got) != len(expected) {
+			t.Fatalf("Input %q: expected %d tokens, got %d", input, len(expected), len(got))
+		}
+		for k := range expected {
+			if !got[k].Equal(expected[k]) || got[k].Line != expected[k].Line || got[k].Column != expected[k].Column {
+				t.Errorf("Input %q token %d: expected %+v, got %+v", input, k, expected[k], got[k])
+			}
+		}
+	}
+}
+
+// Test PrettyPrint formatting
+func TestPrettyPrint(t *testing.T) {
+	tokens, _ := NewLexer("x = 1").TokenizeAll()
+	out := PrettyPrint(tokens)
+
+	if strings.Count(out, "\n") != 3 {
+		t.Errorf("Expected one line per token, got %q", out)
+	}
+	if !strings.Contains(out, "Type: IDENT           Literal: 'x'") {
+		t.Errorf("Unexpected format: %q", out)
+	}
+	if !strings.Contains(out, "Type: ASSIGN          Literal: '='") {
+		t.Errorf("Expected type names in output, got %q", out)
+	}
+}
+
+func benchmarkSource() string {
+	return strings.Repeat("let total = count * 3.14 + \"label\"; // note\n", 1200)
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	input := benchmarkSource()
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		NewLexer(input).WriteTo(&buf)
+	}
+}
+
+func BenchmarkTokenizeAllPrettyPrint(b *testing.B) {
+	input := benchmarkSource()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tokens, _ := NewLexer(input).TokenizeAll()
+		_ = PrettyPrint(tokens)
+	}
+}
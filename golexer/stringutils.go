@@ -0,0 +1,134 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+String Literal Utilities
+Helpers for working with the body of a string literal (the text between
+the quotes) outside of a full tokenization pass. Escape decoding reuses
+the lexer's own escape handling so both always agree on what is valid.
+*/
+
+package golexer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidateStringEscape checks the escape sequences in a string literal
+// body and returns the first invalid one as an error
+func ValidateStringEscape(s string) error {
+	_, err := UnescapeString(s)
+	return err
+}
+
+// UnescapeString applies all escape sequences in a string literal body.
+// On error it returns the partially decoded string and the first error.
+func UnescapeString(s string) (string, error) {
+	var result strings.Builder
+	l := NewLexer(s)
+
+	for l.position < len(l.input) {
+		if l.ch == '\\' {
+			if l.peekChar() == '$' {
+				l.readChar()
+				result.WriteRune('$')
+			} else if char := l.readEscapeSequence(); char != -1 {
+				result.WriteRune(char)
+			}
+		} else {
+			result.WriteRune(l.ch)
+		}
+		l.readChar()
+	}
+
+	if l.HasErrors() {
+		return result.String(), l.errors[0]
+	}
+	return result.String(), nil
+}
+
+// EscapeString escapes special and non-printable characters so that s can
+// be used as the body of a double-quoted string literal
+func EscapeString(s string) string {
+	var result strings.Builder
+	runes := []rune(s)
+
+	for i, r := range runes {
+		switch r {
+		case '\\':
+			result.WriteString(`\\`)
+		case '"':
+			result.WriteString(`\"`)
+		case '\a':
+			result.WriteString(`\a`)
+		case '\b':
+			result.WriteString(`\b`)
+		case '\f':
+			result.WriteString(`\f`)
+		case '\n':
+			result.WriteString(`\n`)
+		case '\r':
+			result.WriteString(`\r`)
+		case '\t':
+			result.WriteString(`\t`)
+		case '\v':
+			result.WriteString(`\v`)
+		case '$':
+			// Only "${" starts interpolation
+			if i+1 < len(runes) && runes[i+1] == '{' {
+				result.WriteString(`\$`)
+			} else {
+				result.WriteRune(r)
+			}
+		default:
+			switch {
+			case unicode.IsPrint(r):
+				result.WriteRune(r)
+			case r < 0x80:
+				fmt.Fprintf(&result, `\x%02X`, r)
+			case r <= 0xFFFF:
+				fmt.Fprintf(&result, `\u%04X`, r)
+			default:
+				fmt.Fprintf(&result, `\U%08X`, r)
+			}
+		}
+	}
+
+	return result.String()
+}
+
+// StripComments returns input with every comment replaced by spaces.
+// Newlines inside block comments are kept, so every remaining token keeps
+// its line and column. On a lexing error it still returns the stripped
+// source along with the first error.
+func StripComments(input string) (string, error) {
+	l := NewLexer(input, WithEmitComments(true))
+	tokens, errs := l.TokenizeAll()
+
+	var sb strings.Builder
+	last := 0
+	for _, tok := range tokens {
+		if !tok.IsComment() {
+			continue
+		}
+		sb.WriteString(input[last:tok.Offset])
+		for _, ch := range tok.SourceText(input) {
+			if ch == '\n' || ch == '\r' {
+				sb.WriteRune(ch)
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		last = tok.EndOffset
+	}
+	sb.WriteString(input[last:])
+
+	if len(errs) > 0 {
+		return sb.String(), errs[0]
+	}
+	return sb.String(), nil
+}
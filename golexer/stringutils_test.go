@@ -0,0 +1,122 @@
+package golexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test every supported escape sequence
+func TestUnescapeString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`plain`, "plain"},
+		{`\a\b\f\n\r\t\v`, "\a\b\f\n\r\t\v"},
+		{`\\ \' \"`, `\ ' "`},
+		{`\0 \101 \7`, "\x00 A \x07"},
+		{`\x41\x7e`, "A~"},
+		{`\u00e9 \U0001F600 \u{1F600}`, "é \U0001F600 \U0001F600"},
+		{`cost \${x}`, "cost ${x}"},
+	}
+
+	for _, tt := range tests {
+		got, err := UnescapeString(tt.input)
+		if err != nil {
+			t.Errorf("Input %q: unexpected error %v", tt.input, err)
+		}
+		if got != tt.expected {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+// Test invalid escapes are reported
+func TestValidateStringEscape(t *testing.T) {
+	if err := ValidateStringEscape(`hello\n`); err != nil {
+		t.Errorf("Expected valid escape, got %v", err)
+	}
+
+	err := ValidateStringEscape(`hello\q`)
+	if err == nil {
+		t.Fatalf("Expected error for \\q")
+	}
+	if !strings.Contains(err.Error(), `\q`) {
+		t.Errorf("Expected error to mention \\q, got %q", err.Error())
+	}
+
+	for _, input := range []string{`\x4`, `\uD800`, `trailing\`} {
+		if ValidateStringEscape(input) == nil {
+			t.Errorf("Input %q: expected error", input)
+		}
+	}
+}
+
+// Test EscapeString round-trips through UnescapeString
+func TestEscapeStringRoundTrip(t *testing.T) {
+	tests := []string{
+		"plain text",
+		"quote \" and backslash \\",
+		"controls \a\b\f\n\r\t\v",
+		"nul \x00 and del \x7f",
+		"unicode \u00e9 \U0001F600 and \u200b",
+		"template ${name} and $5",
+	}
+
+	for _, input := range tests {
+		escaped := EscapeString(input)
+		if strings.ContainsAny(escaped, "\n\t\x00") {
+			t.Errorf("Input %q: escaped form %q still has raw control characters", input, escaped)
+		}
+
+		got, err := UnescapeString(escaped)
+		if err != nil {
+			t.Errorf("Input %q: unexpected error %v", input, err)
+		}
+		if got != input {
+			t.Errorf("Round trip: expected %q, got %q (escaped %q)", input, got, escaped)
+		}
+
+		// The escaped form must also lex as the same STRING literal
+		tok := NewLexer(`"` + escaped + `"`).NextToken()
+		if tok.Type != STRING || tok.Literal != input {
+			t.Errorf("Lexing %q: expected STRING %q, got %s %q", escaped, input, tok.Type, tok.Literal)
+		}
+	}
+}
+
+// Test replacing comments with spaces
+func TestStripComments(t *testing.T) {
+	input := "/**\n * Adds two numbers.\n */\nfn add(a, b) { // sum\n    return a /* plus */ + b\n}\nlet s = \"// not a comment\""
+
+	stripped, err := StripComments(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	withoutString := strings.Replace(stripped, "\"// not a comment\"", "", 1)
+	if strings.Contains(withoutString, "//") || strings.Contains(withoutString, "/*") || strings.Contains(withoutString, "*/") {
+		t.Errorf("Expected no comments left, got %q", stripped)
+	}
+	if !strings.Contains(stripped, "\"// not a comment\"") {
+		t.Errorf("Expected comment-like text in strings to be kept, got %q", stripped)
+	}
+	if strings.Count(stripped, "\n") != strings.Count(input, "\n") {
+		t.Errorf("Expected line count to be preserved")
+	}
+
+	original, _ := NewLexer(input).TokenizeAll()
+	relexed, _ := NewLexer(stripped).TokenizeAll()
+	if !TokenSliceEqual(original, relexed) {
+		t.Fatalf("Expected the same tokens, got %v", relexed)
+	}
+	for i := range original {
+		if original[i].Line != relexed[i].Line || original[i].Column != relexed[i].Column {
+			t.Errorf("Token %q: expected %d:%d, got %d:%d", original[i].Literal, original[i].Line, original[i].Column, relexed[i].Line, relexed[i].Column)
+		}
+	}
+
+	if _, err := StripComments("x /* open"); err == nil {
+		t.Errorf("Expected an error for an unterminated comment")
+	}
+}
@@ -22,102 +22,368 @@ control flow keywords, and various literal types.
 
 package golexer
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // TokenType represents the type of a token
 type TokenType string
 
 // Token represents a single token with its type, literal value, and position
 type Token struct {
-	Type    TokenType
-	Literal string
-	Line    int
-	Column  int
+	Type      TokenType
+	Literal   string
+	Line      int
+	Column    int // 1-based, counted in runes rather than bytes
+	Offset    int // byte offset of the token start in the input
+	EndOffset int // byte offset just past the end of the token
+
+	// SourceFile is the name given with WithSourceName, if any
+	SourceFile string
+
+	// HasLeadingNewline is set when a newline comes between this token and
+	// the last one before it that is not whitespace or a comment
+	HasLeadingNewline bool
+
+	// FirstOnLine is set when only whitespace and comments come before the
+	// token on its line, including for the first token of the input
+	FirstOnLine bool
+
+	// Set by AttachComments
+	LeadingComment  *Token
+	TrailingComment *Token
 }
 
 // Token type constants
 const (
-	ILLEGAL  = "ILLEGAL"
-	EOF      = "EOF"
-	ASSIGN   = "="
-	PLUS     = "+"
-	MINUS    = "-"
-	MULTIPLY = "*"
-	DIVIDE   = "/"
-	NUMBER   = "NUMBER"
-	QUESTION = "?"
-	MODULUS  = "%"
+	ILLEGAL  TokenType = "ILLEGAL"
+	EOF      TokenType = "EOF"
+	ASSIGN   TokenType = "="
+	PLUS     TokenType = "+"
+	MINUS    TokenType = "-"
+	MULTIPLY TokenType = "*"
+	DIVIDE   TokenType = "/"
+	NUMBER   TokenType = "NUMBER"
+	FLOAT    TokenType = "FLOAT"
+	INT      TokenType = "INT"
+	QUESTION TokenType = "?"
+	MODULUS  TokenType = "%"
 	// Logical operators
-	BANG = "!"
-	AND  = "&&"
-	OR   = "||"
+	BANG TokenType = "!"
+	AND  TokenType = "&&"
+	OR   TokenType = "||"
 
 	// Comparison operators
-	NOT_EQL          = "!="
-	LESS_THAN        = "<"
-	LESS_THAN_EQL    = "<="
-	GREATER_THAN     = ">"
-	GREATER_THAN_EQL = ">="
-	EQL              = "=="
+	NOT_EQL          TokenType = "!="
+	LESS_THAN        TokenType = "<"
+	LESS_THAN_EQL    TokenType = "<="
+	GREATER_THAN     TokenType = ">"
+	GREATER_THAN_EQL TokenType = ">="
+	EQL              TokenType = "=="
+	SPACESHIP        TokenType = "<=>"
+
+	// Shift operators
+	LEFT_SHIFT                  TokenType = "<<"
+	LEFT_SHIFT_ASSIGN           TokenType = "<<="
+	RIGHT_SHIFT                 TokenType = ">>"
+	RIGHT_SHIFT_ASSIGN          TokenType = ">>="
+	UNSIGNED_RIGHT_SHIFT        TokenType = ">>>"
+	UNSIGNED_RIGHT_SHIFT_ASSIGN TokenType = ">>>="
+
+	// Bitwise operators
+	CARET      TokenType = "^"
+	XOR_ASSIGN TokenType = "^="
+
+	// Conditional operators
+	NULL_COALESCE TokenType = "??"
+	OPT_CHAIN     TokenType = "?."
+
+	// Annotations, and '@' on its own as a matrix multiplication operator
+	AT         TokenType = "@"
+	ANNOTATION TokenType = "ANNOTATION"
+
+	// Pattern matching operators
+	TILDE        TokenType = "~"
+	TILDE_ASSIGN TokenType = "~="
+	MATCH_ASSIGN TokenType = "=~"
 
 	// Assignment operators
-	PLUS_ASSIGN     = "+="
-	MINUS_ASSIGN    = "-="
-	MULTIPLY_ASSIGN = "*="
-	DIVIDE_ASSIGN   = "/="
-	MODULUS_ASSIGN  = "%="
-	INCREMENT       = "++"
-	DECREMENT       = "--"
+	PLUS_ASSIGN     TokenType = "+="
+	MINUS_ASSIGN    TokenType = "-="
+	MULTIPLY_ASSIGN TokenType = "*="
+	DIVIDE_ASSIGN   TokenType = "/="
+	MODULUS_ASSIGN  TokenType = "%="
+	INCREMENT       TokenType = "++"
+	DECREMENT       TokenType = "--"
 	// Delimiters
-	COMMA           = ","
-	SEMICOLON       = ";"
-	COLON           = ":"
-	DOT             = "."
-	BACKTICK_STRING = "BACKTICK_STRING"
+	COMMA           TokenType = ","
+	SEMICOLON       TokenType = ";"
+	COLON           TokenType = ":"
+	DOT             TokenType = "."
+	BACKTICK_STRING TokenType = "BACKTICK_STRING"
+	TRIPLE_STRING   TokenType = "TRIPLE_STRING"
+	TRIPLE_BACKTICK TokenType = "TRIPLE_BACKTICK"
+	RAW_STRING      TokenType = "RAW_STRING"
+	BYTE_STRING     TokenType = "BYTE_STRING"
+	HEREDOC         TokenType = "HEREDOC"
+	FSTRING_START   TokenType = "FSTRING_START"
+	FSTRING_MIDDLE  TokenType = "FSTRING_MIDDLE"
+	FSTRING_END     TokenType = "FSTRING_END"
 	// Brackets
-	LPAREN   = "("
-	RPAREN   = ")"
-	LBRACE   = "{"
-	RBRACE   = "}"
-	LBRACKET = "["
-	RBRACKET = "]"
+	LPAREN   TokenType = "("
+	RPAREN   TokenType = ")"
+	LBRACE   TokenType = "{"
+	RBRACE   TokenType = "}"
+	LBRACKET TokenType = "["
+	RBRACKET TokenType = "]"
 
 	// Identifiers and Keywords
-	IDENT       = "IDENT"
-	LET         = "LET"
-	CONST       = "CONST"
-	FN          = "FN"
-	IF          = "IF"
-	ELSE        = "ELSE"
-	WHILE       = "WHILE"
-	FOR         = "FOR"
-	RETURN      = "RETURN"
-	BREAK       = "BREAK"
-	CONTINUE    = "CONTINUE"
-	TRUE        = "TRUE"
-	FALSE       = "FALSE"
-	NULL        = "NULL"
-	STRING      = "STRING"
-	STRING_PART = "STRING_PART"
-	INTERP_END  = "INTERP_END"
+	IDENT       TokenType = "IDENT"
+	LET         TokenType = "LET"
+	CONST       TokenType = "CONST"
+	FN          TokenType = "FN"
+	IF          TokenType = "IF"
+	ELSE        TokenType = "ELSE"
+	WHILE       TokenType = "WHILE"
+	FOR         TokenType = "FOR"
+	RETURN      TokenType = "RETURN"
+	BREAK       TokenType = "BREAK"
+	CONTINUE    TokenType = "CONTINUE"
+	TRUE        TokenType = "TRUE"
+	FALSE       TokenType = "FALSE"
+	NULL        TokenType = "NULL"
+	STRING      TokenType = "STRING"
+	STRING_PART TokenType = "STRING_PART"
+	INTERP_END  TokenType = "INTERP_END"
 
 	// Type tokens
-	TYPE_INT    = "TYPE_INT"
-	TYPE_FLOAT  = "TYPE_FLOAT"
-	TYPE_STRING = "TYPE_STRING"
-	TYPE_BOOL   = "TYPE_BOOL"
-	TYPE_CHAR   = "TYPE_CHAR"
-	CHAR        = "CHAR"
-	ARROW       = "->"
-	PIPE        = "|>"
-	DEFAULT     = "DEFAULT"
-	CASE        = "CASE"
-	SWITCH      = "SWITCH"
-	IN          = "IN"
-	TABLE       = "TABLE"
-	USE         = "USE"
-	SPAWN       = "SPAWN"
-	TRY         = "TRY"
+	TYPE_INT    TokenType = "TYPE_INT"
+	TYPE_FLOAT  TokenType = "TYPE_FLOAT"
+	TYPE_STRING TokenType = "TYPE_STRING"
+	TYPE_BOOL   TokenType = "TYPE_BOOL"
+	TYPE_CHAR   TokenType = "TYPE_CHAR"
+	CHAR        TokenType = "CHAR"
+	ARROW       TokenType = "->"
+	PIPE        TokenType = "|>"
+	DEFAULT     TokenType = "DEFAULT"
+	CASE        TokenType = "CASE"
+	SWITCH      TokenType = "SWITCH"
+	IN          TokenType = "IN"
+	TABLE       TokenType = "TABLE"
+	USE         TokenType = "USE"
+	SPAWN       TokenType = "SPAWN"
+	TRY         TokenType = "TRY"
+
+	// Whitespace token (emitted with WithEmitWhitespace)
+	WHITESPACE TokenType = "WHITESPACE"
+
+	// Newline token (emitted with WithEmitNewlines)
+	NEWLINE TokenType = "NEWLINE"
+
+	// Comment tokens (emitted with WithEmitComments)
+	LINE_COMMENT  TokenType = "LINE_COMMENT"
+	BLOCK_COMMENT TokenType = "BLOCK_COMMENT"
+	DOC_COMMENT   TokenType = "DOC_COMMENT"
+
+	// Indentation tokens (emitted by IndentLexer)
+	INDENT TokenType = "INDENT"
+	DEDENT TokenType = "DEDENT"
 )
 
+// tokenTypeNames maps each token type to the name of its constant
+var tokenTypeNames = map[TokenType]string{
+	ILLEGAL:                     "ILLEGAL",
+	EOF:                         "EOF",
+	ASSIGN:                      "ASSIGN",
+	PLUS:                        "PLUS",
+	MINUS:                       "MINUS",
+	MULTIPLY:                    "MULTIPLY",
+	DIVIDE:                      "DIVIDE",
+	NUMBER:                      "NUMBER",
+	FLOAT:                       "FLOAT",
+	INT:                         "INT",
+	QUESTION:                    "QUESTION",
+	MODULUS:                     "MODULUS",
+	BANG:                        "BANG",
+	AND:                         "AND",
+	OR:                          "OR",
+	NOT_EQL:                     "NOT_EQL",
+	LESS_THAN:                   "LESS_THAN",
+	LESS_THAN_EQL:               "LESS_THAN_EQL",
+	GREATER_THAN:                "GREATER_THAN",
+	GREATER_THAN_EQL:            "GREATER_THAN_EQL",
+	EQL:                         "EQL",
+	SPACESHIP:                   "SPACESHIP",
+	LEFT_SHIFT:                  "LEFT_SHIFT",
+	LEFT_SHIFT_ASSIGN:           "LEFT_SHIFT_ASSIGN",
+	RIGHT_SHIFT:                 "RIGHT_SHIFT",
+	RIGHT_SHIFT_ASSIGN:          "RIGHT_SHIFT_ASSIGN",
+	UNSIGNED_RIGHT_SHIFT:        "UNSIGNED_RIGHT_SHIFT",
+	UNSIGNED_RIGHT_SHIFT_ASSIGN: "UNSIGNED_RIGHT_SHIFT_ASSIGN",
+	CARET:                       "CARET",
+	XOR_ASSIGN:                  "XOR_ASSIGN",
+	AT:                          "AT",
+	ANNOTATION:                  "ANNOTATION",
+	NULL_COALESCE:               "NULL_COALESCE",
+	OPT_CHAIN:                   "OPT_CHAIN",
+	TILDE:                       "TILDE",
+	TILDE_ASSIGN:                "TILDE_ASSIGN",
+	MATCH_ASSIGN:                "MATCH_ASSIGN",
+	PLUS_ASSIGN:                 "PLUS_ASSIGN",
+	MINUS_ASSIGN:                "MINUS_ASSIGN",
+	MULTIPLY_ASSIGN:             "MULTIPLY_ASSIGN",
+	DIVIDE_ASSIGN:               "DIVIDE_ASSIGN",
+	MODULUS_ASSIGN:              "MODULUS_ASSIGN",
+	INCREMENT:                   "INCREMENT",
+	DECREMENT:                   "DECREMENT",
+	COMMA:                       "COMMA",
+	SEMICOLON:                   "SEMICOLON",
+	COLON:                       "COLON",
+	DOT:                         "DOT",
+	BACKTICK_STRING:             "BACKTICK_STRING",
+	TRIPLE_STRING:               "TRIPLE_STRING",
+	TRIPLE_BACKTICK:             "TRIPLE_BACKTICK",
+	RAW_STRING:                  "RAW_STRING",
+	BYTE_STRING:                 "BYTE_STRING",
+	HEREDOC:                     "HEREDOC",
+	FSTRING_START:               "FSTRING_START",
+	FSTRING_MIDDLE:              "FSTRING_MIDDLE",
+	FSTRING_END:                 "FSTRING_END",
+	LPAREN:                      "LPAREN",
+	RPAREN:                      "RPAREN",
+	LBRACE:                      "LBRACE",
+	RBRACE:                      "RBRACE",
+	LBRACKET:                    "LBRACKET",
+	RBRACKET:                    "RBRACKET",
+	IDENT:                       "IDENT",
+	LET:                         "LET",
+	CONST:                       "CONST",
+	FN:                          "FN",
+	IF:                          "IF",
+	ELSE:                        "ELSE",
+	WHILE:                       "WHILE",
+	FOR:                         "FOR",
+	RETURN:                      "RETURN",
+	BREAK:                       "BREAK",
+	CONTINUE:                    "CONTINUE",
+	TRUE:                        "TRUE",
+	FALSE:                       "FALSE",
+	NULL:                        "NULL",
+	STRING:                      "STRING",
+	STRING_PART:                 "STRING_PART",
+	INTERP_END:                  "INTERP_END",
+	TYPE_INT:                    "TYPE_INT",
+	TYPE_FLOAT:                  "TYPE_FLOAT",
+	TYPE_STRING:                 "TYPE_STRING",
+	TYPE_BOOL:                   "TYPE_BOOL",
+	TYPE_CHAR:                   "TYPE_CHAR",
+	CHAR:                        "CHAR",
+	ARROW:                       "ARROW",
+	PIPE:                        "PIPE",
+	DEFAULT:                     "DEFAULT",
+	CASE:                        "CASE",
+	SWITCH:                      "SWITCH",
+	IN:                          "IN",
+	TABLE:                       "TABLE",
+	USE:                         "USE",
+	SPAWN:                       "SPAWN",
+	TRY:                         "TRY",
+	WHITESPACE:                  "WHITESPACE",
+	NEWLINE:                     "NEWLINE",
+	LINE_COMMENT:                "LINE_COMMENT",
+	BLOCK_COMMENT:               "BLOCK_COMMENT",
+	DOC_COMMENT:                 "DOC_COMMENT",
+	INDENT:                      "INDENT",
+	DEDENT:                      "DEDENT",
+}
+
+// String returns the constant name of the token type, such as "NOT_EQL"
+// for "!=". Types without a constant, such as those added through a
+// config file, are returned unchanged.
+func (tt TokenType) String() string {
+	if name, ok := tokenTypeNames[tt]; ok {
+		return name
+	}
+	return string(tt)
+}
+
+// registeredTypes contains every token type constant, filled in from
+// tokenTypeNames at init
+var registeredTypes = map[TokenType]struct{}{}
+
+// IsKnown reports whether the token type is one of the built-in constants.
+// Types added through a config file are not known.
+func (tt TokenType) IsKnown() bool {
+	_, ok := registeredTypes[tt]
+	return ok
+}
+
+// Validate returns nil for a built-in token type and a descriptive error
+// otherwise, suggesting the closest built-in type when one is similar
+func (tt TokenType) Validate() error {
+	if tt.IsKnown() {
+		return nil
+	}
+	if suggestion, ok := suggestTokenType(tt); ok {
+		return fmt.Errorf("unknown token type %q (did you mean %q?)", string(tt), string(suggestion))
+	}
+	return fmt.Errorf("unknown token type %q", string(tt))
+}
+
+// suggestTokenType returns the built-in type whose constant name is
+// closest to tt, ignoring case, if it is within a typo's distance. A
+// constant name whose value differs, such as "ASSIGN" for "=", suggests
+// that constant.
+func suggestTokenType(tt TokenType) (TokenType, bool) {
+	upper := strings.ToUpper(string(tt))
+	names := make([]string, 0, len(tokenTypeNames))
+	byName := make(map[string]TokenType, len(tokenTypeNames))
+	for tokType, name := range tokenTypeNames {
+		names = append(names, name)
+		byName[name] = tokType
+	}
+	sort.Strings(names)
+
+	best, bestDistance := "", -1
+	for _, name := range names {
+		limit := 2
+		if len(name) <= 4 {
+			limit = 1
+		}
+		d := editDistance(upper, name)
+		if d <= limit && (bestDistance < 0 || d < bestDistance) {
+			best, bestDistance = name, d
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+	return byName[best], true
+}
+
+// editDistance returns the Levenshtein distance between two ASCII strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
 // keywords maps string literals to their corresponding token types
 var keywords = map[string]TokenType{
 	"let":      LET,
@@ -145,8 +411,379 @@ var keywords = map[string]TokenType{
 
 // LookupIdent checks if an identifier is a keyword and returns the appropriate token type
 func LookupIdent(ident string) TokenType {
-	if tok, ok := keywords[ident]; ok {
+	if tok, ok := lookupKeyword(ident); ok {
 		return tok
 	}
 	return IDENT
 }
+
+// literalTypes contains token types that carry a literal value, along
+// with the quotes that open and close an f-string
+var literalTypes = map[TokenType]struct{}{
+	NUMBER:          {},
+	FLOAT:           {},
+	INT:             {},
+	STRING:          {},
+	STRING_PART:     {},
+	CHAR:            {},
+	BACKTICK_STRING: {},
+	TRIPLE_STRING:   {},
+	TRIPLE_BACKTICK: {},
+	RAW_STRING:      {},
+	BYTE_STRING:     {},
+	HEREDOC:         {},
+	FSTRING_START:   {},
+	FSTRING_MIDDLE:  {},
+	FSTRING_END:     {},
+}
+
+// operatorTypes contains all operator token types
+var operatorTypes = map[TokenType]struct{}{
+	ASSIGN:           {},
+	PLUS:             {},
+	MINUS:            {},
+	MULTIPLY:         {},
+	DIVIDE:           {},
+	MODULUS:          {},
+	BANG:             {},
+	AND:              {},
+	OR:               {},
+	NOT_EQL:          {},
+	LESS_THAN:        {},
+	LESS_THAN_EQL:    {},
+	GREATER_THAN:     {},
+	GREATER_THAN_EQL: {},
+	EQL:              {},
+	SPACESHIP:        {},
+	TILDE:            {},
+	TILDE_ASSIGN:     {},
+	MATCH_ASSIGN:     {},
+	PLUS_ASSIGN:      {},
+	MINUS_ASSIGN:     {},
+	MULTIPLY_ASSIGN:  {},
+	DIVIDE_ASSIGN:    {},
+	MODULUS_ASSIGN:   {},
+	INCREMENT:        {},
+	DECREMENT:        {},
+	ARROW:            {},
+	PIPE:             {},
+	QUESTION:         {},
+
+	LEFT_SHIFT:                  {},
+	LEFT_SHIFT_ASSIGN:           {},
+	RIGHT_SHIFT:                 {},
+	RIGHT_SHIFT_ASSIGN:          {},
+	UNSIGNED_RIGHT_SHIFT:        {},
+	UNSIGNED_RIGHT_SHIFT_ASSIGN: {},
+	NULL_COALESCE:               {},
+	OPT_CHAIN:                   {},
+	CARET:                       {},
+	XOR_ASSIGN:                  {},
+	AT:                          {},
+}
+
+// keywordTypes contains all token types produced by the keywords map
+var keywordTypes = map[TokenType]struct{}{}
+
+// typeTypes contains the built-in type name tokens
+var typeTypes = map[TokenType]struct{}{
+	TYPE_INT:    {},
+	TYPE_FLOAT:  {},
+	TYPE_STRING: {},
+	TYPE_BOOL:   {},
+	TYPE_CHAR:   {},
+}
+
+// delimiterTypes contains punctuation and bracket token types
+var delimiterTypes = map[TokenType]struct{}{
+	COMMA:      {},
+	SEMICOLON:  {},
+	COLON:      {},
+	DOT:        {},
+	LPAREN:     {},
+	RPAREN:     {},
+	LBRACE:     {},
+	RBRACE:     {},
+	LBRACKET:   {},
+	RBRACKET:   {},
+	INTERP_END: {},
+	INDENT:     {},
+	DEDENT:     {},
+}
+
+// commentTypes contains the comment token types
+var commentTypes = map[TokenType]struct{}{
+	LINE_COMMENT:  {},
+	BLOCK_COMMENT: {},
+	DOC_COMMENT:   {},
+}
+
+func init() {
+	for _, tokType := range keywords {
+		keywordTypes[tokType] = struct{}{}
+	}
+	for tokType := range tokenTypeNames {
+		registeredTypes[tokType] = struct{}{}
+	}
+}
+
+// IsLiteral reports whether the token is a number, string or character literal
+func (t Token) IsLiteral() bool {
+	_, ok := literalTypes[t.Type]
+	return ok
+}
+
+// IsOperator reports whether the token is an arithmetic, logical, comparison or assignment operator
+func (t Token) IsOperator() bool {
+	_, ok := operatorTypes[t.Type]
+	return ok
+}
+
+// IsKeyword reports whether the token is one of the built-in keywords
+func (t Token) IsKeyword() bool {
+	_, ok := keywordTypes[t.Type]
+	return ok
+}
+
+// IsType reports whether the token is a built-in type name
+func (t Token) IsType() bool {
+	_, ok := typeTypes[t.Type]
+	return ok
+}
+
+// IsDelimiter reports whether the token is punctuation or a bracket
+func (t Token) IsDelimiter() bool {
+	_, ok := delimiterTypes[t.Type]
+	return ok
+}
+
+// IsIdentifier reports whether the token is a plain identifier or an
+// annotation such as @decorator
+func (t Token) IsIdentifier() bool {
+	return t.Type == IDENT || t.Type == ANNOTATION
+}
+
+// IsComment reports whether the token is a line, block or doc comment
+func (t Token) IsComment() bool {
+	_, ok := commentTypes[t.Type]
+	return ok
+}
+
+// precedences maps binary operators to their binding strength.
+// Higher values bind tighter; assignments bind loosest.
+var precedences = map[TokenType]int{
+	ASSIGN:           0,
+	PLUS_ASSIGN:      0,
+	MINUS_ASSIGN:     0,
+	MULTIPLY_ASSIGN:  0,
+	DIVIDE_ASSIGN:    0,
+	MODULUS_ASSIGN:   0,
+	PIPE:             1,
+	NULL_COALESCE:    2,
+	OR:               3,
+	AND:              4,
+	EQL:              5,
+	NOT_EQL:          5,
+	TILDE_ASSIGN:     5,
+	MATCH_ASSIGN:     5,
+	LESS_THAN:        6,
+	LESS_THAN_EQL:    6,
+	GREATER_THAN:     6,
+	GREATER_THAN_EQL: 6,
+	SPACESHIP:        6,
+	PLUS:             8,
+	MINUS:            8,
+	MULTIPLY:         9,
+	DIVIDE:           9,
+	MODULUS:          9,
+
+	LEFT_SHIFT_ASSIGN:           0,
+	RIGHT_SHIFT_ASSIGN:          0,
+	UNSIGNED_RIGHT_SHIFT_ASSIGN: 0,
+	LEFT_SHIFT:                  7,
+	RIGHT_SHIFT:                 7,
+	UNSIGNED_RIGHT_SHIFT:        7,
+	XOR_ASSIGN:                  0,
+	CARET:                       8,
+	AT:                          9,
+}
+
+// rightAssociative contains the binary operators that group from the right
+var rightAssociative = map[TokenType]struct{}{
+	ASSIGN:          {},
+	PLUS_ASSIGN:     {},
+	MINUS_ASSIGN:    {},
+	MULTIPLY_ASSIGN: {},
+	DIVIDE_ASSIGN:   {},
+	MODULUS_ASSIGN:  {},
+
+	LEFT_SHIFT_ASSIGN:           {},
+	RIGHT_SHIFT_ASSIGN:          {},
+	UNSIGNED_RIGHT_SHIFT_ASSIGN: {},
+	XOR_ASSIGN:                  {},
+}
+
+// Precedence returns the binding strength of a binary operator, or -1
+// if the token type is not a binary operator
+func (tt TokenType) Precedence() int {
+	if prec, ok := precedences[tt]; ok {
+		return prec
+	}
+	return -1
+}
+
+// IsLeftAssociative reports whether a binary operator groups from the left
+func (tt TokenType) IsLeftAssociative() bool {
+	return tt.Precedence() >= 0 && !tt.IsRightAssociative()
+}
+
+// IsRightAssociative reports whether a binary operator groups from the right
+func (tt TokenType) IsRightAssociative() bool {
+	_, ok := rightAssociative[tt]
+	return ok
+}
+
+// Equal reports whether two tokens have the same type and literal, ignoring position
+func (t Token) Equal(other Token) bool {
+	return t.Type == other.Type && t.Literal == other.Literal
+}
+
+// EqualType reports whether two tokens have the same type
+func (t Token) EqualType(other Token) bool {
+	return t.Type == other.Type
+}
+
+// EqualLiteral reports whether two tokens have the same literal
+func (t Token) EqualLiteral(other Token) bool {
+	return t.Literal == other.Literal
+}
+
+// TokenSliceEqual reports whether two token slices are pairwise Equal
+func TokenSliceEqual(a, b []Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TokenSliceEqualType reports whether two token slices have the same sequence of types
+func TokenSliceEqualType(a, b []Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].EqualType(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewToken creates a token at the given line and column
+func NewToken(tt TokenType, lit string, line, col int) Token {
+	return Token{Type: tt, Literal: lit, Line: line, Column: col}
+}
+
+// NewTokenWithOffset creates a token with a full position including its byte offset
+func NewTokenWithOffset(tt TokenType, lit string, line, col, offset int) Token {
+	return Token{Type: tt, Literal: lit, Line: line, Column: col, Offset: offset}
+}
+
+// WithLine returns a copy of the token on line n
+func (t Token) WithLine(n int) Token {
+	t.Line = n
+	return t
+}
+
+// WithColumn returns a copy of the token at column n
+func (t Token) WithColumn(n int) Token {
+	t.Column = n
+	return t
+}
+
+// WithLiteral returns a copy of the token with literal s
+func (t Token) WithLiteral(s string) Token {
+	t.Literal = s
+	return t
+}
+
+// SourceText returns the token exactly as written in input, including
+// quotes and escape sequences. It returns an empty string if the token's
+// offsets do not fall within input.
+func (t Token) SourceText(input string) string {
+	if t.Offset < 0 || t.EndOffset < t.Offset || t.EndOffset > len(input) {
+		return ""
+	}
+	return input[t.Offset:t.EndOffset]
+}
+
+// String returns a compact description such as [IDENT "foo" 3:7]
+func (t Token) String() string {
+	return fmt.Sprintf("[%s %q %d:%d]", t.Type.String(), t.Literal, t.Line, t.Column)
+}
+
+// GoString returns the token as a Go composite literal for %#v
+func (t Token) GoString() string {
+	return fmt.Sprintf("Token{Type: %q, Literal: %q, Line: %d, Column: %d}", string(t.Type), t.Literal, t.Line, t.Column)
+}
+
+// IsFirstOnLine reports whether only whitespace and comments come before
+// the token on its line
+func (t Token) IsFirstOnLine() bool {
+	return t.FirstOnLine
+}
+
+// IsLastOnLine reports whether next, the token after t, starts a new line
+func (t Token) IsLastOnLine(next Token) bool {
+	return next.Line > t.Line || next.Type == EOF
+}
+
+// FirstLine returns the lowest line number in tokens, or 0 if there are none
+func FirstLine(tokens []Token) int {
+	first := 0
+	for _, tok := range tokens {
+		if first == 0 || tok.Line < first {
+			first = tok.Line
+		}
+	}
+	return first
+}
+
+// LastLine returns the highest line number in tokens, or 0 if there are none
+func LastLine(tokens []Token) int {
+	last := 0
+	for _, tok := range tokens {
+		if tok.Line > last {
+			last = tok.Line
+		}
+	}
+	return last
+}
+
+// LastColumn returns the column of the rightmost token on the last line,
+// or 0 if there are no tokens
+func LastColumn(tokens []Token) int {
+	line, column := 0, 0
+	for _, tok := range tokens {
+		if tok.Line > line || (tok.Line == line && tok.Column > column) {
+			line, column = tok.Line, tok.Column
+		}
+	}
+	return column
+}
+
+// TokensOnLine returns the tokens on the given line
+func TokensOnLine(tokens []Token, line int) []Token {
+	var result []Token
+	for _, tok := range tokens {
+		if tok.Line == line {
+			result = append(result, tok)
+		}
+	}
+	return result
+}
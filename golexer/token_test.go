@@ -0,0 +1,518 @@
+package golexer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Test that every built-in token type belongs to exactly one class
+func TestTokenClassifiers(t *testing.T) {
+	tests := []struct {
+		tokType  TokenType
+		expected string
+	}{
+		{NUMBER, "literal"},
+		{FLOAT, "literal"},
+		{INT, "literal"},
+		{STRING, "literal"},
+		{STRING_PART, "literal"},
+		{CHAR, "literal"},
+		{BACKTICK_STRING, "literal"},
+		{TRIPLE_STRING, "literal"},
+		{TRIPLE_BACKTICK, "literal"},
+		{RAW_STRING, "literal"},
+		{BYTE_STRING, "literal"},
+		{HEREDOC, "literal"},
+		{FSTRING_START, "literal"},
+		{FSTRING_MIDDLE, "literal"},
+		{FSTRING_END, "literal"},
+
+		{ASSIGN, "operator"},
+		{PLUS, "operator"},
+		{MINUS, "operator"},
+		{MULTIPLY, "operator"},
+		{DIVIDE, "operator"},
+		{MODULUS, "operator"},
+		{BANG, "operator"},
+		{AND, "operator"},
+		{OR, "operator"},
+		{NOT_EQL, "operator"},
+		{LESS_THAN, "operator"},
+		{LESS_THAN_EQL, "operator"},
+		{GREATER_THAN, "operator"},
+		{GREATER_THAN_EQL, "operator"},
+		{EQL, "operator"},
+		{SPACESHIP, "operator"},
+		{TILDE, "operator"},
+		{TILDE_ASSIGN, "operator"},
+		{MATCH_ASSIGN, "operator"},
+		{LEFT_SHIFT, "operator"},
+		{LEFT_SHIFT_ASSIGN, "operator"},
+		{RIGHT_SHIFT, "operator"},
+		{RIGHT_SHIFT_ASSIGN, "operator"},
+		{UNSIGNED_RIGHT_SHIFT, "operator"},
+		{UNSIGNED_RIGHT_SHIFT_ASSIGN, "operator"},
+		{PLUS_ASSIGN, "operator"},
+		{MINUS_ASSIGN, "operator"},
+		{MULTIPLY_ASSIGN, "operator"},
+		{DIVIDE_ASSIGN, "operator"},
+		{MODULUS_ASSIGN, "operator"},
+		{INCREMENT, "operator"},
+		{DECREMENT, "operator"},
+		{ARROW, "operator"},
+		{PIPE, "operator"},
+		{QUESTION, "operator"},
+		{NULL_COALESCE, "operator"},
+		{OPT_CHAIN, "operator"},
+		{CARET, "operator"},
+		{XOR_ASSIGN, "operator"},
+		{AT, "operator"},
+
+		{LET, "keyword"},
+		{CONST, "keyword"},
+		{FN, "keyword"},
+		{IF, "keyword"},
+		{ELSE, "keyword"},
+		{WHILE, "keyword"},
+		{FOR, "keyword"},
+		{RETURN, "keyword"},
+		{BREAK, "keyword"},
+		{CONTINUE, "keyword"},
+		{TRUE, "keyword"},
+		{FALSE, "keyword"},
+		{NULL, "keyword"},
+		{DEFAULT, "keyword"},
+		{CASE, "keyword"},
+		{SWITCH, "keyword"},
+		{IN, "keyword"},
+		{TABLE, "keyword"},
+		{USE, "keyword"},
+		{SPAWN, "keyword"},
+		{TRY, "keyword"},
+
+		{TYPE_INT, "type"},
+		{TYPE_FLOAT, "type"},
+		{TYPE_STRING, "type"},
+		{TYPE_BOOL, "type"},
+		{TYPE_CHAR, "type"},
+
+		{COMMA, "delimiter"},
+		{SEMICOLON, "delimiter"},
+		{COLON, "delimiter"},
+		{DOT, "delimiter"},
+		{LPAREN, "delimiter"},
+		{RPAREN, "delimiter"},
+		{LBRACE, "delimiter"},
+		{RBRACE, "delimiter"},
+		{LBRACKET, "delimiter"},
+		{RBRACKET, "delimiter"},
+		{INTERP_END, "delimiter"},
+		{INDENT, "delimiter"},
+		{DEDENT, "delimiter"},
+
+		{IDENT, "identifier"},
+		{ANNOTATION, "identifier"},
+
+		{LINE_COMMENT, "comment"},
+		{BLOCK_COMMENT, "comment"},
+		{DOC_COMMENT, "comment"},
+	}
+
+	expected := make(map[TokenType]string, len(tests))
+	for _, tt := range tests {
+		expected[tt.tokType] = tt.expected
+	}
+	for tokType := range tokenTypeNames {
+		if _, ok := expected[tokType]; !ok && !slices.Contains(unclassifiedTypes, tokType) {
+			t.Errorf("Type %s: missing from the classifier table", tokType)
+		}
+	}
+
+	for _, tt := range tests {
+		tok := Token{Type: tt.tokType}
+		classes := map[string]bool{
+			"literal":    tok.IsLiteral(),
+			"operator":   tok.IsOperator(),
+			"keyword":    tok.IsKeyword(),
+			"type":       tok.IsType(),
+			"delimiter":  tok.IsDelimiter(),
+			"identifier": tok.IsIdentifier(),
+			"comment":    tok.IsComment(),
+		}
+
+		for class, got := range classes {
+			if got != (class == tt.expected) {
+				t.Errorf("Type %s: expected only %s, but Is%s returned %v", tt.tokType, tt.expected, class, got)
+			}
+		}
+	}
+}
+
+// unclassifiedTypes are the token types no classifier matches: trivia,
+// EOF and ILLEGAL
+var unclassifiedTypes = []TokenType{EOF, ILLEGAL, WHITESPACE, NEWLINE}
+
+// Test that special tokens are not classified
+func TestTokenClassifiersSpecial(t *testing.T) {
+	for _, tokType := range unclassifiedTypes {
+		tok := Token{Type: tokType}
+		if tok.IsLiteral() || tok.IsOperator() || tok.IsKeyword() || tok.IsType() || tok.IsDelimiter() || tok.IsIdentifier() || tok.IsComment() {
+			t.Errorf("Type %s: expected no classifier to match", tokType)
+		}
+	}
+}
+
+// Test operator precedence ordering
+func TestPrecedence(t *testing.T) {
+	if MULTIPLY.Precedence() <= PLUS.Precedence() {
+		t.Errorf("Expected * to bind tighter than +")
+	}
+	if EQL.Precedence() >= LESS_THAN.Precedence() {
+		t.Errorf("Expected == to bind looser than <")
+	}
+	if AND.Precedence() <= OR.Precedence() {
+		t.Errorf("Expected && to bind tighter than ||")
+	}
+	if NULL_COALESCE.Precedence() >= OR.Precedence() || NULL_COALESCE.Precedence() <= ASSIGN.Precedence() {
+		t.Errorf("Expected ?? to bind looser than || and tighter than =")
+	}
+	if !NULL_COALESCE.IsLeftAssociative() {
+		t.Errorf("Expected ?? to be left associative")
+	}
+	if ASSIGN.Precedence() >= OR.Precedence() {
+		t.Errorf("Expected = to bind looser than ||")
+	}
+	for _, tokType := range []TokenType{IDENT, NUMBER, LPAREN, EOF} {
+		if tokType.Precedence() != -1 {
+			t.Errorf("Type %s: expected precedence -1, got %d", tokType, tokType.Precedence())
+		}
+	}
+}
+
+// Test operator associativity
+func TestAssociativity(t *testing.T) {
+	if !ASSIGN.IsRightAssociative() {
+		t.Errorf("Expected = to be right associative")
+	}
+	if PLUS.IsRightAssociative() {
+		t.Errorf("Expected + not to be right associative")
+	}
+	if !PLUS.IsLeftAssociative() {
+		t.Errorf("Expected + to be left associative")
+	}
+	if IDENT.IsLeftAssociative() || IDENT.IsRightAssociative() {
+		t.Errorf("Expected IDENT to have no associativity")
+	}
+}
+
+// Test token equality ignores position
+func TestTokenEqual(t *testing.T) {
+	a := Token{Type: IDENT, Literal: "foo", Line: 1, Column: 1}
+	b := Token{Type: IDENT, Literal: "foo", Line: 7, Column: 12}
+	c := Token{Type: IDENT, Literal: "bar", Line: 1, Column: 1}
+	d := Token{Type: STRING, Literal: "foo", Line: 1, Column: 1}
+
+	if !a.Equal(b) {
+		t.Errorf("Expected tokens at different positions to be equal")
+	}
+	if a.Equal(c) || a.Equal(d) {
+		t.Errorf("Expected tokens with different type or literal to differ")
+	}
+	if !a.EqualType(c) || a.EqualType(d) {
+		t.Errorf("EqualType returned wrong result")
+	}
+	if !a.EqualLiteral(d) || a.EqualLiteral(c) {
+		t.Errorf("EqualLiteral returned wrong result")
+	}
+}
+
+// Test token slice comparison
+func TestTokenSliceEqual(t *testing.T) {
+	first, _ := NewLexer("let x = 5;").TokenizeAll()
+	second, _ := NewLexer("let   x =\n5 ;").TokenizeAll()
+	renamed, _ := NewLexer("let y = 5;").TokenizeAll()
+
+	if !TokenSliceEqual(first, second) {
+		t.Errorf("Expected identical token sequences to be equal")
+	}
+	if TokenSliceEqual(first, renamed) {
+		t.Errorf("Expected sequences with different literals to differ")
+	}
+	if !TokenSliceEqualType(first, renamed) {
+		t.Errorf("Expected sequences with same types to be type-equal")
+	}
+	if TokenSliceEqual(first, first[:3]) || TokenSliceEqualType(first, first[:3]) {
+		t.Errorf("Expected slices of different lengths to differ")
+	}
+}
+
+// Test TokenType names
+func TestTokenTypeString(t *testing.T) {
+	tests := []struct {
+		tokType  TokenType
+		expected string
+	}{
+		{NOT_EQL, "NOT_EQL"},
+		{ASSIGN, "ASSIGN"},
+		{LPAREN, "LPAREN"},
+		{IDENT, "IDENT"},
+		{UNSIGNED_RIGHT_SHIFT_ASSIGN, "UNSIGNED_RIGHT_SHIFT_ASSIGN"},
+		{TokenType("CUSTOM"), "CUSTOM"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.tokType.String(); got != tt.expected {
+			t.Errorf("TokenType %q: expected %s, got %s", string(tt.tokType), tt.expected, got)
+		}
+	}
+
+	// Names must be unique so they can be mapped back to types
+	seen := make(map[string]TokenType)
+	for tokType, name := range tokenTypeNames {
+		if prev, ok := seen[name]; ok {
+			t.Errorf("Name %s used by both %q and %q", name, string(prev), string(tokType))
+		}
+		seen[name] = tokType
+	}
+}
+
+// Test that every token type constant is known and nothing else is
+func TestTokenTypeIsKnown(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "token.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	constants := 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				literal, err := strconv.Unquote(value.Values[i].(*ast.BasicLit).Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tokType := TokenType(literal); !tokType.IsKnown() || tokenTypeNames[tokType] != name.Name {
+					t.Errorf("Constant %s (%q) is not a known token type", name.Name, literal)
+				}
+				constants++
+			}
+		}
+	}
+	if constants != len(tokenTypeNames) {
+		t.Errorf("Expected %d constants in token.go, found %d", len(tokenTypeNames), constants)
+	}
+
+	for _, tokType := range []TokenType{"garbage", "", "ident", "IDNET", "UNLESS"} {
+		if tokType.IsKnown() {
+			t.Errorf("TokenType %q: expected unknown", string(tokType))
+		}
+		if tokType.Validate() == nil {
+			t.Errorf("TokenType %q: expected a validation error", string(tokType))
+		}
+	}
+	if err := IDENT.Validate(); err != nil {
+		t.Errorf("Expected IDENT to be valid, got %v", err)
+	}
+}
+
+// Test token constructors and builders
+func TestNewToken(t *testing.T) {
+	tokens, _ := NewLexer("let x = 5;\nlet y = 6;").TokenizeAll()
+
+	let := NewToken(LET, "let", 1, 1)
+	expected := []Token{
+		let,
+		NewToken(IDENT, "x", 1, 5),
+		NewToken(ASSIGN, "=", 1, 7),
+		NewToken(NUMBER, "5", 1, 9),
+		NewToken(SEMICOLON, ";", 1, 10),
+		let.WithLine(2),
+		NewToken(IDENT, "x", 2, 5).WithLiteral("y"),
+		NewToken(ASSIGN, "=", 2, 7),
+		NewToken(NUMBER, "6", 2, 0).WithColumn(9),
+		NewToken(SEMICOLON, ";", 2, 10),
+	}
+
+	if !TokenSliceEqual(tokens, expected) {
+		t.Fatalf("Expected %v, got %v", expected, tokens)
+	}
+	for i := range expected {
+		if tokens[i].Line != expected[i].Line || tokens[i].Column != expected[i].Column {
+			t.Errorf("Token %d: expected %d:%d, got %d:%d", i, expected[i].Line, expected[i].Column, tokens[i].Line, tokens[i].Column)
+		}
+	}
+
+	// Builders must not modify the original token
+	if let.Line != 1 {
+		t.Errorf("Expected WithLine to return a copy")
+	}
+
+	tok := NewTokenWithOffset(IDENT, "y", 2, 5, 15)
+	if !tok.Equal(tokens[6]) || tok.Offset != tokens[6].Offset {
+		t.Errorf("Expected %v, got %v", tok, tokens[6])
+	}
+}
+
+// Test recovering the original source text of tokens
+func TestSourceText(t *testing.T) {
+	input := `let s = "hello\nworld"; x += 0xFF; c = '\t';`
+	tokens, _ := NewLexer(input).TokenizeAll()
+
+	expected := []string{"let", "s", "=", `"hello\nworld"`, ";", "x", "+=", "0xFF", ";", "c", "=", `'\t'`, ";"}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d", len(expected), len(tokens))
+	}
+	for i, tok := range tokens {
+		if got := tok.SourceText(input); got != expected[i] {
+			t.Errorf("Token %d (%s): expected source %q, got %q", i, tok.Type, expected[i], got)
+		}
+	}
+	if tokens[3].Literal != "hello\nworld" {
+		t.Errorf("Expected STRING literal to stay unescaped, got %q", tokens[3].Literal)
+	}
+
+	// Interpolated parts cover their own spans
+	input = `"a${b + 1}c"`
+	tokens, _ = NewLexer(input).TokenizeAll()
+	expected = []string{`"a`, "b", "+", "1", "}", `c"`}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d interpolation tokens, got %d", len(expected), len(tokens))
+	}
+	for i, tok := range tokens {
+		if got := tok.SourceText(input); got != expected[i] {
+			t.Errorf("Token %d (%s): expected source %q, got %q", i, tok.Type, expected[i], got)
+		}
+	}
+
+	if src := NewToken(IDENT, "x", 1, 1).SourceText(""); src != "" {
+		t.Errorf("Expected empty source for out of range token, got %q", src)
+	}
+}
+
+// Test line and column helpers over token slices
+func TestTokenLineHelpers(t *testing.T) {
+	if LastLine(nil) != 0 || FirstLine(nil) != 0 || LastColumn(nil) != 0 {
+		t.Errorf("Expected 0 for an empty slice")
+	}
+
+	single := []Token{NewToken(IDENT, "x", 3, 7)}
+	if LastLine(single) != 3 || FirstLine(single) != 3 || LastColumn(single) != 7 {
+		t.Errorf("Expected line 3 column 7 for a single token")
+	}
+
+	tokens, _ := NewLexer("\nlet x = 1;\nlet yy = 2;\n\nfoo(bar)").TokenizeAll()
+	if FirstLine(tokens) != 2 || LastLine(tokens) != 5 {
+		t.Errorf("Expected lines 2 to 5, got %d to %d", FirstLine(tokens), LastLine(tokens))
+	}
+	if LastColumn(tokens) != 8 {
+		t.Errorf("Expected last column 8, got %d", LastColumn(tokens))
+	}
+
+	expected := []Token{
+		NewToken(LET, "let", 3, 1),
+		NewToken(IDENT, "yy", 3, 5),
+		NewToken(ASSIGN, "=", 3, 8),
+		NewToken(NUMBER, "2", 3, 10),
+		NewToken(SEMICOLON, ";", 3, 11),
+	}
+	if got := TokensOnLine(tokens, 3); !TokenSliceEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if got := TokensOnLine(tokens, 4); len(got) != 0 {
+		t.Errorf("Expected no tokens on the blank line, got %v", got)
+	}
+}
+
+// Test line position helpers
+func TestLinePositionHelpers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []bool // IsFirstOnLine for each token
+	}{
+		{"a\nb", []bool{true, true}},
+		{"a b", []bool{true, false}},
+		{"  a b\n\n    c d", []bool{true, false, true, false}},
+		{"a // note\nb", []bool{true, true}},
+		{"a\n/* note */ b", []bool{true, true}},
+		{"x = \"s${y}t\"\nz", []bool{true, false, false, false, false, false, true}},
+	}
+
+	for _, tt := range tests {
+		tokens, _ := NewLexer(tt.input).TokenizeAll()
+		if len(tokens) != len(tt.expected) {
+			t.Errorf("Input %q: expected %d tokens, got %v", tt.input, len(tt.expected), tokens)
+			continue
+		}
+		for i, expected := range tt.expected {
+			if tokens[i].IsFirstOnLine() != expected {
+				t.Errorf("Input %q[%d]: expected IsFirstOnLine %v, got %v", tt.input, i, expected, tokens[i].IsFirstOnLine())
+			}
+		}
+	}
+
+	tokens, _ := NewLexer("a\nb").TokenizeAll()
+	if !tokens[1].HasLeadingNewline {
+		t.Errorf("Expected HasLeadingNewline for 'b' in \"a\\nb\"")
+	}
+	tokens, _ = NewLexer("a b").TokenizeAll()
+	if tokens[1].HasLeadingNewline {
+		t.Errorf("Expected no HasLeadingNewline for 'b' in \"a b\"")
+	}
+	if tokens[0].HasLeadingNewline || !tokens[0].IsFirstOnLine() {
+		t.Errorf("Expected the first token to be first on its line without a leading newline")
+	}
+
+	// Emitted whitespace, newlines and comments do not hide the line start
+	for _, opt := range []Option{WithEmitWhitespace(false), WithEmitWhitespace(true), WithEmitNewlines(true), WithEmitComments(true)} {
+		for _, input := range []string{"a\n b", "a // note\n b", "a /* x\ny */ b"} {
+			tokens, _ := NewLexer(input, opt).TokenizeAll()
+			b := tokens[len(tokens)-1]
+			if b.Literal != "b" || !b.IsFirstOnLine() || !b.HasLeadingNewline {
+				t.Errorf("Input %q: expected 'b' first on its line after a newline, got %v (first %v, newline %v)", input, b, b.IsFirstOnLine(), b.HasLeadingNewline)
+			}
+		}
+	}
+
+	l := NewLexer("a b\nc")
+	a, b, c, eof := l.NextToken(), l.NextToken(), l.NextToken(), l.NextToken()
+	if a.IsLastOnLine(b) || !b.IsLastOnLine(c) || !c.IsLastOnLine(eof) {
+		t.Errorf("Expected only 'b' and 'c' to be last on their lines")
+	}
+}
+
+// Test the string forms of a token
+func TestTokenString(t *testing.T) {
+	tok := NewToken(IDENT, "foo", 3, 7)
+
+	if tok.String() != `[IDENT "foo" 3:7]` {
+		t.Errorf("Expected [IDENT \"foo\" 3:7], got %s", tok.String())
+	}
+	if fmt.Sprint(tok) != tok.String() {
+		t.Errorf("Expected fmt.Sprint to use String, got %s", fmt.Sprint(tok))
+	}
+	if s := NewToken(STRING, "a\"b", 1, 1).String(); !strings.Contains(s, `"a\"b"`) {
+		t.Errorf("Expected quoted literal, got %s", s)
+	}
+	if s := NewToken(NOT_EQL, "!=", 2, 4).String(); s != `[NOT_EQL "!=" 2:4]` {
+		t.Errorf("Expected type name in string, got %s", s)
+	}
+
+	goString := fmt.Sprintf("%#v", tok)
+	if goString != `Token{Type: "IDENT", Literal: "foo", Line: 3, Column: 7}` {
+		t.Errorf("Expected Go syntax, got %s", goString)
+	}
+	if !strings.Contains(fmt.Sprintf("%#v", NewToken(ASSIGN, "=", 1, 2)), `Type: "="`) {
+		t.Errorf("Expected GoString to use the type value")
+	}
+}
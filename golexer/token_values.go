@@ -0,0 +1,125 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Token Value Helpers
+Helpers for interpreting the literal text of tokens, so parsers can
+pick the right strconv function without re-examining the source.
+*/
+
+package golexer
+
+import "strings"
+
+// IsNumber reports whether the token is a numeric literal
+func (t Token) IsNumber() bool {
+	return t.Type == NUMBER || t.Type == FLOAT || t.Type == INT
+}
+
+// NumberBase returns the base of a numeric literal: 16 for 0x, 2 for 0b,
+// 8 for 0o or a leading zero followed by octal digits, and 10 otherwise.
+// Underscore separators are ignored. It returns -1 for non-numeric tokens.
+func (t Token) NumberBase() int {
+	if !t.IsNumber() {
+		return -1
+	}
+
+	lit := strings.ReplaceAll(t.Literal, "_", "")
+	if len(lit) < 2 || lit[0] != '0' {
+		return 10
+	}
+
+	switch lit[1] {
+	case 'x', 'X':
+		return 16
+	case 'b', 'B':
+		return 2
+	case 'o', 'O':
+		return 8
+	}
+
+	for _, ch := range lit[1:] {
+		if ch < '0' || ch > '7' {
+			return 10
+		}
+	}
+	return 8
+}
+
+// StringKind classifies a string literal: "raw" for backtick and
+// r-prefixed strings, "byte" for b-prefixed strings, "template" for the
+// text of interpolated and format strings and "unicode" for other strings.
+// It returns an empty string for tokens that are not string literals.
+func (t Token) StringKind() string {
+	switch t.Type {
+	case RAW_STRING, BACKTICK_STRING, TRIPLE_BACKTICK:
+		return "raw"
+	case BYTE_STRING:
+		return "byte"
+	case STRING_PART, FSTRING_MIDDLE:
+		return "template"
+	case STRING, TRIPLE_STRING, HEREDOC:
+		return "unicode"
+	}
+	return ""
+}
+
+// splitNumberSuffix splits a numeric literal into the number and any type
+// suffix after it, and reports whether the number is a float
+func splitNumberSuffix(lit string) (number, suffix string, isFloat bool) {
+	digitsFrom := func(i int, isDigit func(byte) bool) int {
+		for i < len(lit) && (isDigit(lit[i]) || lit[i] == '_') {
+			i++
+		}
+		return i
+	}
+	decimal := func(b byte) bool { return b >= '0' && b <= '9' }
+
+	if len(lit) > 1 && lit[0] == '0' && strings.IndexByte("xXbBoO", lit[1]) >= 0 {
+		i := digitsFrom(2, func(b byte) bool { return isHexDigit(rune(b)) })
+		return lit[:i], lit[i:], false
+	}
+
+	i := digitsFrom(0, decimal)
+	if i < len(lit) && lit[i] == '.' {
+		isFloat = true
+		i = digitsFrom(i+1, decimal)
+	}
+	if i < len(lit) && (lit[i] == 'e' || lit[i] == 'E') {
+		j := i + 1
+		if j < len(lit) && (lit[j] == '+' || lit[j] == '-') {
+			j++
+		}
+		if j < len(lit) && decimal(lit[j]) {
+			isFloat = true
+			i = digitsFrom(j, decimal)
+		}
+	}
+	return lit[:i], lit[i:], isFloat
+}
+
+// FloatSuffix returns the type suffix of a float literal, such as "f64"
+// in 2.0f64, or an empty string if it has none or is not a float
+func (t Token) FloatSuffix() string {
+	if !t.IsNumber() {
+		return ""
+	}
+	if _, suffix, isFloat := splitNumberSuffix(t.Literal); isFloat {
+		return suffix
+	}
+	return ""
+}
+
+// IntSuffix returns the type suffix of an integer literal, such as "u8"
+// in 0xFFu8, or an empty string if it has none or is not an integer
+func (t Token) IntSuffix() string {
+	if !t.IsNumber() {
+		return ""
+	}
+	if _, suffix, isFloat := splitNumberSuffix(t.Literal); !isFloat {
+		return suffix
+	}
+	return ""
+}
@@ -0,0 +1,171 @@
+package golexer
+
+import (
+	"testing"
+)
+
+// Test detecting the base of numeric literals
+func TestNumberBase(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"42", 10},
+		{"0", 10},
+		{"3.14", 10},
+		{"0.5", 10},
+		{"1e10", 10},
+		{"0xFF", 16},
+		{"0XAB", 16},
+		{"0b1010", 2},
+		{"0B11", 2},
+		{"0o77", 8},
+		{"0O17", 8},
+		{"0777", 8},
+	}
+
+	for _, tt := range tests {
+		tok := NewLexer(tt.input).NextToken()
+		if !tok.IsNumber() {
+			t.Errorf("Input %q: expected NUMBER, got %s", tt.input, tok.Type)
+			continue
+		}
+		if base := tok.NumberBase(); base != tt.expected {
+			t.Errorf("Input %q: expected base %d, got %d", tt.input, tt.expected, base)
+		}
+	}
+
+	separated := []struct {
+		literal  string
+		expected int
+	}{
+		{"1_000", 10},
+		{"0x_FF", 16},
+		{"0_777", 8},
+	}
+	for _, tt := range separated {
+		if base := NewToken(NUMBER, tt.literal, 1, 1).NumberBase(); base != tt.expected {
+			t.Errorf("Literal %q: expected base %d, got %d", tt.literal, tt.expected, base)
+		}
+	}
+
+	if base := NewToken(IDENT, "0x1", 1, 1).NumberBase(); base != -1 {
+		t.Errorf("Expected -1 for non-numeric token, got %d", base)
+	}
+}
+
+// Test classifying string literals
+func TestStringKind(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"hello"`, "unicode"},
+		{`"""multi"""`, "unicode"},
+		{"`raw`", "raw"},
+		{"```raw```", "raw"},
+		{`r"raw"`, "raw"},
+		{`b"bytes"`, "byte"},
+		{`"a ${b} c"`, "template"},
+		{`'c'`, ""},
+		{`42`, ""},
+		{`name`, ""},
+	}
+
+	for _, tt := range tests {
+		tok := NewLexer(tt.input).NextToken()
+		if kind := tok.StringKind(); kind != tt.expected {
+			t.Errorf("Input %q: expected %q, got %q", tt.input, tt.expected, kind)
+		}
+	}
+}
+
+// Test float literals with type suffixes
+func TestFloatSuffix(t *testing.T) {
+	suffixes := WithFloatSuffixes([]string{"f32", "f64", "d128"})
+	tests := []struct {
+		input    string
+		expected TokenType
+		literal  string
+		suffix   string
+	}{
+		{"3.14f32", FLOAT, "3.14f32", "f32"},
+		{"2.0f64", FLOAT, "2.0f64", "f64"},
+		{"1.5d128", FLOAT, "1.5d128", "d128"},
+		{"1e10f64", FLOAT, "1e10f64", "f64"},
+		{".5f32", FLOAT, ".5f32", "f32"},
+		{"3.14", FLOAT, "3.14", ""},
+		{"42", NUMBER, "42", ""},
+		{"3.14foobar", ILLEGAL, "3.14foobar", ""},
+		{"3.14f32x", ILLEGAL, "3.14f32x", ""},
+		{"3.14f16", ILLEGAL, "3.14f16", ""},
+		{"3f32", ILLEGAL, "3f32", ""},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input, suffixes)
+		tok := l.NextToken()
+		if tok.Type != tt.expected || tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected %s %q, got %s %q", tt.input, tt.expected, tt.literal, tok.Type, tok.Literal)
+		}
+		if suffix := tok.FloatSuffix(); suffix != tt.suffix {
+			t.Errorf("Input %q: expected suffix %q, got %q", tt.input, tt.suffix, suffix)
+		}
+		if hasErrors := tt.expected == ILLEGAL; l.HasErrors() != hasErrors {
+			t.Errorf("Input %q: expected errors %v, got %v", tt.input, hasErrors, l.GetErrors())
+		}
+	}
+
+	// The same suffixes are accepted by default, and none once cleared
+	if tok := NewLexer("3.14f32").NextToken(); tok.Type != FLOAT || tok.FloatSuffix() != "f32" {
+		t.Errorf("Expected FLOAT with the default suffixes, got %s %q", tok.Type, tok.Literal)
+	}
+	if tok := NewLexer("3.14f32", WithFloatSuffixes(nil)).NextToken(); tok.Type != ILLEGAL {
+		t.Errorf("Expected ILLEGAL without suffixes, got %s", tok.Type)
+	}
+}
+
+// Test integer literals with type suffixes
+func TestIntSuffix(t *testing.T) {
+	suffixes := WithIntSuffixes([]string{"u8", "u16", "u32", "u64", "usize", "i8", "i16", "i32", "i64", "isize"})
+	tests := []struct {
+		input    string
+		expected TokenType
+		literal  string
+		suffix   string
+	}{
+		{"42u32", INT, "42u32", "u32"},
+		{"0xFFu8", INT, "0xFFu8", "u8"},
+		{"0b1010i16", INT, "0b1010i16", "i16"},
+		{"0o17u64", INT, "0o17u64", "u64"},
+		{"0755i32", INT, "0755i32", "i32"},
+		{"100isize", INT, "100isize", "isize"},
+		{"42", NUMBER, "42", ""},
+		{"42.0u32", ILLEGAL, "42.0u32", ""},
+		{"1e3i64", ILLEGAL, "1e3i64", ""},
+		{"42u128", ILLEGAL, "42u128", ""},
+		{"42u8x", ILLEGAL, "42u8x", ""},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input, suffixes)
+		tok := l.NextToken()
+		if tok.Type != tt.expected || tok.Literal != tt.literal {
+			t.Errorf("Input %q: expected %s %q, got %s %q", tt.input, tt.expected, tt.literal, tok.Type, tok.Literal)
+		}
+		if suffix := tok.IntSuffix(); suffix != tt.suffix {
+			t.Errorf("Input %q: expected suffix %q, got %q", tt.input, tt.suffix, suffix)
+		}
+		if tok.FloatSuffix() != "" {
+			t.Errorf("Input %q: expected no float suffix, got %q", tt.input, tok.FloatSuffix())
+		}
+		if hasErrors := tt.expected == ILLEGAL; l.HasErrors() != hasErrors {
+			t.Errorf("Input %q: expected errors %v, got %v", tt.input, hasErrors, l.GetErrors())
+		}
+	}
+
+	// Suffixes are only accepted when configured
+	if tok := NewLexer("42u32").NextToken(); tok.Type != ILLEGAL {
+		t.Errorf("Expected ILLEGAL without configured suffixes, got %s", tok.Type)
+	}
+}
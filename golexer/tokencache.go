@@ -0,0 +1,106 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Token Cache Files
+Saves tokenization results to disk with encoding/gob so large sources
+need not be lexed again on the next run. A cache saved with
+SaveTokenCacheFor records a SHA-256 checksum of the source it was made
+from, so a stale cache can be detected before it is used.
+*/
+
+package golexer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"fmt"
+	"os"
+)
+
+// tokenCacheVersion changes whenever the cache file layout changes
+const tokenCacheVersion = 2
+
+// tokenCacheFile is the gob-encoded contents of a cache file. Checksum
+// is nil when the cache was saved without its source.
+type tokenCacheFile struct {
+	Version  int
+	Checksum []byte
+	Tokens   []Token
+	Errors   []*LexError
+}
+
+// SaveTokenCache writes tokens and errors to path. The cache records no
+// source checksum, so IsTokenCacheValid reports false for it; use
+// SaveTokenCacheFor when the source is at hand.
+func SaveTokenCache(tokens []Token, errors []*LexError, path string) error {
+	return saveTokenCache(nil, tokens, errors, path)
+}
+
+// SaveTokenCacheFor writes the tokens and errors produced from source to
+// path, with a checksum of source for IsTokenCacheValid
+func SaveTokenCacheFor(source string, tokens []Token, errors []*LexError, path string) error {
+	checksum := sha256.Sum256([]byte(source))
+	return saveTokenCache(checksum[:], tokens, errors, path)
+}
+
+func saveTokenCache(checksum []byte, tokens []Token, errors []*LexError, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	cache := tokenCacheFile{
+		Version:  tokenCacheVersion,
+		Checksum: checksum,
+		Tokens:   tokens,
+		Errors:   errors,
+	}
+	if err := gob.NewEncoder(f).Encode(&cache); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadTokenCache reads the tokens and errors saved at path. Use
+// IsTokenCacheValid first to check the cache matches the source.
+func LoadTokenCache(path string) ([]Token, LexErrorSlice, error) {
+	cache, err := readTokenCache(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cache.Tokens, cache.Errors, nil
+}
+
+// IsTokenCacheValid reports whether the cache at path was saved for source
+// with SaveTokenCacheFor
+func IsTokenCacheValid(path, source string) bool {
+	cache, err := readTokenCache(path)
+	if err != nil || cache.Checksum == nil {
+		return false
+	}
+	checksum := sha256.Sum256([]byte(source))
+	return bytes.Equal(cache.Checksum, checksum[:])
+}
+
+// readTokenCache decodes a cache file and checks its version
+func readTokenCache(path string) (*tokenCacheFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cache tokenCacheFile
+	if err := gob.NewDecoder(f).Decode(&cache); err != nil {
+		return nil, fmt.Errorf("invalid token cache %s: %w", path, err)
+	}
+	if cache.Version != tokenCacheVersion {
+		return nil, fmt.Errorf("token cache %s has version %d, expected %d", path, cache.Version, tokenCacheVersion)
+	}
+	return &cache, nil
+}
@@ -0,0 +1,67 @@
+package golexer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Test a token cache round trips every field
+func TestTokenCacheRoundTrip(t *testing.T) {
+	source := "/// doc\nlet x = \"hé ${y}\";\nfn § 0xFF"
+	tokens, errors := NewLexer(source, WithEmitComments(true), WithSourceName("main.lang")).TokenizeAll()
+	tokens = AttachComments(tokens)
+	path := filepath.Join(t.TempDir(), "tokens.cache")
+
+	if err := SaveTokenCache(tokens, errors, path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded, loadedErrors, err := LoadTokenCache(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, tokens) {
+		t.Errorf("Expected %+v, got %+v", tokens, loaded)
+	}
+	if len(loadedErrors) != 1 || !reflect.DeepEqual(loadedErrors[0], errors[0]) {
+		t.Errorf("Expected %v, got %v", errors, loadedErrors)
+	}
+	if loaded[0].LeadingComment == nil || loaded[0].LeadingComment.Type != DOC_COMMENT {
+		t.Errorf("Expected attached doc comment to survive, got %+v", loaded[0])
+	}
+}
+
+// Test cache validity follows the source checksum
+func TestIsTokenCacheValid(t *testing.T) {
+	source := "let x = 1;"
+	tokens, errors := NewLexer(source).TokenizeAll()
+	path := filepath.Join(t.TempDir(), "tokens.cache")
+
+	if IsTokenCacheValid(path, source) {
+		t.Errorf("Expected a missing cache to be invalid")
+	}
+	if err := SaveTokenCache(tokens, errors, path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if IsTokenCacheValid(path, source) {
+		t.Errorf("Expected a cache saved without its source to be invalid")
+	}
+	if err := SaveTokenCacheFor(source, tokens, errors, path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !IsTokenCacheValid(path, source) {
+		t.Errorf("Expected cache to be valid for its source")
+	}
+	if IsTokenCacheValid(path, "let x = 2;") {
+		t.Errorf("Expected cache to be invalid after a one byte change")
+	}
+
+	if err := os.WriteFile(path, []byte("not a cache"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadTokenCache(path); err == nil {
+		t.Errorf("Expected error for a corrupt cache")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Tree-sitter Grammar Export
+Writes a grammar.json skeleton for tree-sitter with one rule per token
+type, as a starting point for a full tree-sitter parser. The start rule
+accepts any sequence of tokens; replace it with real syntax rules.
+*/
+
+package golexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// treeSitterNode is a node in a tree-sitter grammar
+type treeSitterNode struct {
+	Type    string           `json:"type"`
+	Value   string           `json:"value,omitempty"`
+	Name    string           `json:"name,omitempty"`
+	Named   bool             `json:"named,omitempty"`
+	Content *treeSitterNode  `json:"content,omitempty"`
+	Members []treeSitterNode `json:"members,omitempty"`
+}
+
+// treeSitterRule is a named rule
+type treeSitterRule struct {
+	name string
+	node treeSitterNode
+}
+
+// treeSitterRules encodes as a JSON object that keeps rule order, since
+// tree-sitter uses the first rule as the start rule
+type treeSitterRules []treeSitterRule
+
+// MarshalJSON writes the rules as an object in order
+func (rules treeSitterRules) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, rule := range rules {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(rule.name)
+		if err != nil {
+			return nil, err
+		}
+		node, err := json.Marshal(rule.node)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(node)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// treeSitterPatterns are the rules for tokens that are not spelled literally
+var treeSitterPatterns = []treeSitterRule{
+	{"int", treeSitterNode{Type: "PATTERN", Value: `(0[xX][0-9a-fA-F]+|0[bB][01]+|0[oO][0-7]+|\d+)[ui](8|16|32|64|size)`}},
+	{"number", treeSitterNode{Type: "PATTERN", Value: `0[xX][0-9a-fA-F]+|0[bB][01]+|0[oO][0-7]+|\d+`}},
+	{"float", treeSitterNode{Type: "PATTERN", Value: `(\d+\.\d+([eE][+-]?\d+)?|\d+[eE][+-]?\d+|\.\d+([eE][+-]?\d+)?)(f32|f64|d128)?`}},
+	{"raw_string", treeSitterNode{Type: "PATTERN", Value: `[rR]("([^"\\]|\\.)*"|'([^'\\]|\\.)*')`}},
+	{"byte_string", treeSitterNode{Type: "PATTERN", Value: `[bB]"([^"\\\n]|\\.)*"`}},
+	{"identifier", treeSitterNode{Type: "PATTERN", Value: `[\p{L}_][\p{L}\p{Nd}_]*`}},
+	{"annotation", treeSitterNode{Type: "PATTERN", Value: `@[\p{L}_][\p{L}\p{Nd}_]*`}},
+	{"string", treeSitterNode{Type: "PATTERN", Value: `"([^"\\\n]|\\.)*"`}},
+	{"char", treeSitterNode{Type: "PATTERN", Value: `'([^'\\\n]|\\.)+'`}},
+	{"triple_backtick", treeSitterNode{Type: "PATTERN", Value: "```([^`]|`[^`]|``[^`])*```"}},
+	{"backtick_string", treeSitterNode{Type: "PATTERN", Value: "`[^`]*`"}},
+	{"comment", treeSitterNode{Type: "CHOICE", Members: []treeSitterNode{
+		{Type: "PATTERN", Value: `//[^\n]*`},
+		{Type: "PATTERN", Value: `/\*[^*]*\*+([^/*][^*]*\*+)*/`},
+	}}},
+}
+
+// treeSitterFormatString returns the rule for an f-string, as
+// FSTRING_START, text and braced expressions, then FSTRING_END. The quote
+// is matched by a separate alternative for each quote character, and expr
+// lists the tokens allowed between the braces.
+func treeSitterFormatString(expr treeSitterNode) treeSitterNode {
+	alias := func(tokType TokenType, node treeSitterNode) treeSitterNode {
+		return treeSitterNode{Type: "ALIAS", Content: &node, Named: true, Value: treeSitterRuleName(tokType)}
+	}
+	immediate := func(pattern string) treeSitterNode {
+		return treeSitterNode{Type: "IMMEDIATE_TOKEN", Content: &treeSitterNode{Type: "PATTERN", Value: pattern}}
+	}
+	braced := treeSitterNode{Type: "SEQ", Members: []treeSitterNode{
+		{Type: "SYMBOL", Name: treeSitterRuleName(LBRACE)},
+		{Type: "REPEAT", Content: &expr},
+		{Type: "SYMBOL", Name: treeSitterRuleName(RBRACE)},
+	}}
+
+	choice := treeSitterNode{Type: "CHOICE"}
+	for _, quote := range []string{`"`, `'`} {
+		text := treeSitterNode{Type: "CHOICE", Members: []treeSitterNode{
+			alias(FSTRING_MIDDLE, immediate(`([^{}`+quote+`\\]|\{\{|\}\}|\\.)+`)),
+			braced,
+		}}
+		choice.Members = append(choice.Members, treeSitterNode{Type: "SEQ", Members: []treeSitterNode{
+			alias(FSTRING_START, treeSitterNode{Type: "PATTERN", Value: "[fF]" + quote}),
+			{Type: "REPEAT", Content: &text},
+			alias(FSTRING_END, immediate(quote)),
+		}})
+	}
+	return choice
+}
+
+// GenerateTreeSitterGrammar writes a tree-sitter grammar.json skeleton
+// with the given grammar name for the current token definitions
+func GenerateTreeSitterGrammar(w io.Writer, name string) error {
+	tokenRules, _, _ := collectTokenRules()
+
+	var rules treeSitterRules
+	for _, rule := range tokenRules {
+		node := treeSitterNode{Type: "STRING", Value: rule.literals[0]}
+		if len(rule.literals) > 1 {
+			node = treeSitterNode{Type: "CHOICE"}
+			for _, literal := range rule.literals {
+				node.Members = append(node.Members, treeSitterNode{Type: "STRING", Value: literal})
+			}
+		}
+		rules = append(rules, treeSitterRule{treeSitterRuleName(rule.tokType), node})
+	}
+	rules = append(rules, treeSitterPatterns...)
+
+	// The start rule accepts any token sequence. An f-string expression
+	// accepts the same tokens apart from braces.
+	const fstring = "fstring"
+	choice := treeSitterNode{Type: "CHOICE", Members: []treeSitterNode{{Type: "SYMBOL", Name: fstring}}}
+	expr := treeSitterNode{Type: "CHOICE", Members: []treeSitterNode{{Type: "SYMBOL", Name: fstring}}}
+	for _, rule := range rules {
+		if rule.name == "comment" {
+			continue
+		}
+		symbol := treeSitterNode{Type: "SYMBOL", Name: rule.name}
+		choice.Members = append(choice.Members, symbol)
+		if rule.name != treeSitterRuleName(LBRACE) && rule.name != treeSitterRuleName(RBRACE) {
+			expr.Members = append(expr.Members, symbol)
+		}
+	}
+	rules = append(rules, treeSitterRule{fstring, treeSitterFormatString(expr)})
+	start := treeSitterRule{"source_file", treeSitterNode{Type: "REPEAT", Content: &choice}}
+	rules = append(treeSitterRules{start}, rules...)
+
+	grammar := struct {
+		Name   string           `json:"name"`
+		Rules  treeSitterRules  `json:"rules"`
+		Extras []treeSitterNode `json:"extras"`
+	}{
+		Name:  name,
+		Rules: rules,
+		Extras: []treeSitterNode{
+			{Type: "PATTERN", Value: `\s`},
+			{Type: "SYMBOL", Name: "comment"},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(grammar)
+}
+
+// treeSitterRuleName returns a tree-sitter rule name for a token type
+func treeSitterRuleName(tokType TokenType) string {
+	return strings.ToLower(antlrRuleName(tokType))
+}
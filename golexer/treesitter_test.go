@@ -0,0 +1,69 @@
+package golexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// Test the tree-sitter grammar skeleton
+func TestGenerateTreeSitterGrammar(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateTreeSitterGrammar(&buf, "mylang"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		t.Fatalf("Expected valid JSON, got %s", buf.String())
+	}
+
+	var grammar struct {
+		Name   string                    `json:"name"`
+		Rules  map[string]map[string]any `json:"rules"`
+		Extras []map[string]any          `json:"extras"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &grammar); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if grammar.Name != "mylang" {
+		t.Errorf("Expected name mylang, got %q", grammar.Name)
+	}
+	for word, tokType := range keywords {
+		rule, ok := grammar.Rules[treeSitterRuleName(tokType)]
+		if !ok {
+			t.Errorf("Expected rule for keyword %q", word)
+			continue
+		}
+		if rule["type"] != "STRING" || rule["value"] != word {
+			t.Errorf("Keyword %q: expected STRING rule, got %v", word, rule)
+		}
+	}
+	if rule := grammar.Rules["not_eql"]; rule["value"] != "!=" {
+		t.Errorf("Expected != operator rule, got %v", rule)
+	}
+	if rule := grammar.Rules["fstring"]; rule["type"] != "CHOICE" || !bytes.Contains(buf.Bytes(), []byte(`"value": "fstring_middle"`)) {
+		t.Errorf("Expected an fstring rule with FSTRING_MIDDLE text, got %v", rule)
+	}
+	if len(grammar.Extras) != 2 {
+		t.Errorf("Expected whitespace and comment extras, got %v", grammar.Extras)
+	}
+
+	// The start rule must come first
+	var first string
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if tok == "rules" {
+			dec.Token() // opening brace
+			key, _ := dec.Token()
+			first, _ = key.(string)
+			break
+		}
+	}
+	if first != "source_file" {
+		t.Errorf("Expected source_file as the first rule, got %q", first)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+GoLexer - A Comprehensive Lexical Analyzer for Go
+Author: Uthman Dev
+GitHub: https://github.com/codetesla51/golexer
+License: MIT
+
+Config Hot Reload
+Watches a config file and reloads it when it changes on disk, for
+long-running tools such as language servers. The directory is watched
+rather than the file itself so editors that save by replacing the file
+are still noticed.
+*/
+
+package golexer
+
+import (
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch reloads the config from path whenever the file changes and passes
+// each newly loaded config to onChange, which runs on the watcher's
+// goroutine. c is the config currently in use: reloads that match it, or
+// match the config passed to onChange last, are not reported, since
+// editors often write a file several times per save. Neither c nor the
+// package tables are modified; onChange decides how to apply the new
+// config, usually by creating lexers with WithConfig. Files that fail to
+// parse are ignored until the next change. The returned stop function
+// ends the watch and releases the file handle.
+func (c *Config) Watch(path string, onChange func(*Config)) (stop func(), err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	path = filepath.Clean(path)
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	last := c
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || !event.Has(fsnotify.Write|fsnotify.Create) {
+					continue
+				}
+				config, err := LoadConfig(path)
+				if err != nil || reflect.DeepEqual(config, last) {
+					continue
+				}
+				last = config
+				if onChange != nil {
+					onChange(config)
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			watcher.Close()
+			<-done
+		})
+	}
+	return stop, nil
+}
@@ -0,0 +1,75 @@
+package golexer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Test reloading a config file when it changes
+func TestConfigWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"additionalKeywords": {"first": "FIRST"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan *Config, 10)
+	stop, err := config.Watch(path, func(c *Config) { reloaded <- c })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer stop()
+
+	if err := os.WriteFile(path, []byte(`{"additionalKeywords": {"watched": "WATCHED"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got *Config
+	timeout := time.After(5 * time.Second)
+	for got == nil || got.AdditionalKeywords["watched"] == "" {
+		select {
+		case got = <-reloaded:
+		case <-timeout:
+			t.Fatal("Timed out waiting for the config to reload")
+		}
+	}
+
+	if got == config {
+		t.Errorf("Expected onChange to get a new config")
+	}
+	if config.AdditionalKeywords["first"] != "FIRST" || config.AdditionalKeywords["watched"] != "" {
+		t.Errorf("Expected the watched config to be left alone, got %v", config.AdditionalKeywords)
+	}
+	if tok := NewLexer("watched", WithConfig(got)).NextToken(); tok.Type != "WATCHED" {
+		t.Errorf("Expected a lexer built from the reloaded config to read WATCHED, got %s", tok.Type)
+	}
+	if tok := NewLexer("watched").NextToken(); tok.Type != IDENT {
+		t.Errorf("Expected the package keywords to be left alone, got %s", tok.Type)
+	}
+
+	// Writing the same config again is not reported
+	if err := os.WriteFile(path, []byte(`{"additionalKeywords": {"watched": "WATCHED"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-reloaded:
+		t.Errorf("Expected an unchanged config not to be reported, got %v", c.AdditionalKeywords)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	stop()
+	stop() // stopping twice is safe
+}
+
+// Test watching a file in a missing directory
+func TestConfigWatchMissingDir(t *testing.T) {
+	var config Config
+	if _, err := config.Watch(filepath.Join(t.TempDir(), "missing", "config.json"), nil); err == nil {
+		t.Errorf("Expected an error for a missing directory")
+	}
+}